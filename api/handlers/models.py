@@ -2,9 +2,12 @@
 API request and response models
 """
 
-from pydantic import BaseModel, Field
-from typing import List, Optional, Dict, Any
+from pydantic import BaseModel, Field, field_validator
+from typing import List, Optional, Dict, Any, Literal
 from datetime import datetime
+from urllib.parse import urlparse
+
+from search_engine.base import PROJECTABLE_FIELDS
 
 # Authentication models
 class LoginRequest(BaseModel):
@@ -26,22 +29,64 @@ class UserInfo(BaseModel):
     login_time: datetime = Field(..., description="Login time")
 
 # Search models
-class TextSearchRequest(BaseModel):
+class SearchOptions(BaseModel):
+    """Search options shared by query search requests"""
+    exact: bool = Field(True, description="Exact vector scan, false uses the approximate ANN index")
+    metric: Optional[Literal['cosine', 'dot_product', 'l2']] = Field(None, description="Vector similarity override, defaults to the index metric")
+    score_normalization: Literal['none', 'per_query_max', 'softmax'] = Field('none', description="Rescaling of returned scores, ordering is unchanged")
+    fields: List[str] = Field([], description="Result fields to return, all when empty")
+    include_vectors: bool = Field(False, description="Return the stored vectors with each result")
+
+    @field_validator('fields')
+    @classmethod
+    def check_fields(cls, value: List[str]) -> List[str]:
+        unknown = [name for name in value if name not in PROJECTABLE_FIELDS and name not in ('id', 'score')]
+        if unknown:
+            raise ValueError(f'Unsupported fields: {unknown}')
+        return value
+
+    def search_options(self) -> Dict[str, Any]:
+        """Options as SearchInput keyword arguments"""
+        return {
+            'exact': self.exact,
+            'metric': self.metric,
+            'score_normalization': self.score_normalization,
+            'fields': self.fields,
+            'include_vectors': self.include_vectors,
+        }
+
+class TextSearchRequest(SearchOptions):
     """Text search request model"""
     query: str = Field(..., description="Search query text")
     top_k: int = Field(10, ge=1, le=100, description="Number of results to return")
 
-class ImageSearchRequest(BaseModel):
+class ImageSearchRequest(SearchOptions):
     """Image search request model"""
     image_url: str = Field(..., description="Image URL")
     top_k: int = Field(10, ge=1, le=100, description="Number of results to return")
 
-class VideoSearchRequest(BaseModel):
+    @field_validator('image_url')
+    @classmethod
+    def check_image_url(cls, value: str) -> str:
+        value = value.strip()
+        if not value:
+            raise ValueError('image_url must not be empty')
+        parsed = urlparse(value)
+        if parsed.scheme not in ('http', 'https') or not parsed.netloc:
+            raise ValueError('image_url must be an http(s) URL')
+        return value
+
+class VideoSearchRequest(SearchOptions):
     """Video search request model"""
     video_url: str = Field(..., description="Video URL")
     top_k: int = Field(10, ge=1, le=100, description="Number of results to return")
 
-class MultimodalSearchRequest(BaseModel):
+class SimilarSearchRequest(BaseModel):
+    """Similar document search request model"""
+    id: str = Field(..., min_length=1, description="ID of the stored document to find neighbours for")
+    top_k: int = Field(10, ge=1, le=100, description="Number of results to return")
+
+class MultimodalSearchRequest(SearchOptions):
     """Multimodal search request model"""
     text: Optional[str] = Field(None, description="Text query")
     image_url: Optional[str] = Field(None, description="Image URL")
@@ -57,6 +102,8 @@ class SearchResultItem(BaseModel):
     image_text: str = Field("", description="Image description text")
     video_text: str = Field("", description="Video description text")
     score: float = Field(0.0, description="Similarity score")
+    highlights: List[str] = Field([], description="Highlighted fragments of keyword-matched text")
+    embeddings: Optional[Dict[str, List[float]]] = Field(None, description="Stored vectors by field, only set when include_vectors is requested")
 
 class SearchResponse(BaseModel):
     """Search response model"""
@@ -83,6 +130,7 @@ class InsertResponse(BaseModel):
     message: str = Field(..., description="Response message")
     inserted_count: int = Field(0, description="Number of successfully inserted items")
     processing_time: float = Field(0.0, description="Processing time")
+    warnings: List[str] = Field([], description="Non-fatal extraction failures, e.g. a skipped caption or transcript")
 
 class DataListItem(BaseModel):
     """Data list item model"""
@@ -107,6 +155,21 @@ class DataListResponse(BaseModel):
     page: int = Field(1, description="Current page number")
     page_size: int = Field(10, description="Items per page")
 
+class DataItemResponse(BaseModel):
+    """Single data item response model"""
+    success: bool = Field(..., description="Query success status")
+    message: str = Field(..., description="Response message")
+    item: DataListItem = Field(..., description="Data item")
+
+class IndexStatsResponse(BaseModel):
+    """Index statistics response model"""
+    success: bool = Field(..., description="Query success status")
+    message: str = Field(..., description="Response message")
+    doc_count: int = Field(0, description="Number of documents")
+    deleted_count: int = Field(0, description="Number of deleted documents not yet merged away")
+    size_bytes: int = Field(0, description="Primary storage size in bytes")
+    vector_dims: Dict[str, int] = Field({}, description="Dimension of each vector field")
+
 # File upload models
 class FileUploadResponse(BaseModel):
     """File upload response model"""
@@ -9,13 +9,14 @@ import traceback
 
 from .models import (
     TextSearchRequest, ImageSearchRequest, VideoSearchRequest, 
-    MultimodalSearchRequest, SearchResponse, SearchResultItem,
+    SimilarSearchRequest, MultimodalSearchRequest, SearchResponse, SearchResultItem,
     InsertDataRequest, BatchInsertRequest, InsertResponse, ErrorResponse,
-    DataListRequest, DataListResponse, DataListItem,
+    DataListRequest, DataListResponse, DataListItem, DataItemResponse, IndexStatsResponse,
     AsyncInsertDataRequest, AsyncBatchInsertRequest, AsyncTaskResponse,
     TaskStatusResponse, TaskStatus, TaskListResponse
 )
 from .search_service import SearchService
+from processor.utils.error_class import classify_error, ErrorClass
 from .auth import get_current_token
 from .exceptions import (
     MoleSearchException, ValidationException, MediaProcessingException,
@@ -44,6 +45,13 @@ def handle_service_exception(e: Exception) -> HTTPException:
         return HTTPException(status_code=422, detail=str(e))
     elif isinstance(e, NotFoundException):
         return HTTPException(status_code=404, detail=str(e))
+    error_class = classify_error(e)
+    if error_class == ErrorClass.RETRYABLE:
+        return HTTPException(status_code=503, detail=str(e))
+    elif error_class == ErrorClass.CLIENT_ERROR:
+        return HTTPException(status_code=400, detail=str(e))
+    elif error_class == ErrorClass.SERVER_ERROR:
+        return HTTPException(status_code=502, detail=str(e))
     elif isinstance(e, ServiceException):
         return HTTPException(status_code=500, detail=str(e))
     else:
@@ -59,6 +67,14 @@ async def get_search_service():
     return search_service
 
 
+async def close_search_service():
+    """Close search service instance"""
+    global search_service
+    if search_service is not None:
+        await search_service.close()
+        search_service = None
+
+
 @router.post("/search/text", response_model=SearchResponse)
 async def search_text(
     request: TextSearchRequest,
@@ -70,6 +86,7 @@ async def search_text(
     
     - **query**: Search query text
     - **top_k**: Return result count, default 10, maximum 100
+    - **exact**, **metric**, **score_normalization**, **fields**, **include_vectors**: Optional search options
     """
     start_time = time.time()
     
@@ -77,7 +94,7 @@ async def search_text(
         logger.info(f"Text search request: {request.query[:100]}...")
         
         # Execute search
-        results = await service.search_text(request.query, request.top_k)
+        results = await service.search_text(request.query, request.top_k, request.search_options())
         
         # Build response
         search_results = []
@@ -93,7 +110,9 @@ async def search_text(
                 video_url=video_url if video_url is not None else '',
                 image_text=item.get('image_text', ''),
                 video_text=item.get('video_text', ''),
-                score=item.get('score', 0.0)
+                score=item.get('score', 0.0),
+                highlights=item.get('highlights', []),
+                embeddings=item.get('embeddings')
             ))
         
         query_time = time.time() - start_time
@@ -109,11 +128,9 @@ async def search_text(
         
     except Exception as e:
         logger.error(f"Text search failed: {str(e)}")
-        logger.error(traceback.format_exc())
-        raise HTTPException(
-            status_code=500,
-            detail=f"Search failed: {str(e)}"
-        )
+        if not isinstance(e, MoleSearchException):
+            logger.error(traceback.format_exc())
+        raise handle_service_exception(e)
 
 
 @router.post("/search/image", response_model=SearchResponse)
@@ -127,14 +144,15 @@ async def search_image(
     
     - **image_url**: Image URL address
     - **top_k**: Return result count, default 10, maximum 100
+    - **exact**, **metric**, **score_normalization**, **fields**, **include_vectors**: Optional search options
     """
     start_time = time.time()
-    
+
     try:
         logger.info(f"Image search request: {request.image_url}")
         
         # Execute search
-        results = await service.search_image(request.image_url, request.top_k)
+        results = await service.search_image(request.image_url, request.top_k, request.search_options())
         
         # Build response
         search_results = []
@@ -150,7 +168,9 @@ async def search_image(
                 video_url=video_url if video_url is not None else '',
                 image_text=item.get('image_text', ''),
                 video_text=item.get('video_text', ''),
-                score=item.get('score', 0.0)
+                score=item.get('score', 0.0),
+                highlights=item.get('highlights', []),
+                embeddings=item.get('embeddings')
             ))
         
         query_time = time.time() - start_time
@@ -182,6 +202,7 @@ async def search_video(
     
     - **video_url**: Video URL address
     - **top_k**: Return result count, default 10, maximum 100
+    - **exact**, **metric**, **score_normalization**, **fields**, **include_vectors**: Optional search options
     """
     start_time = time.time()
     
@@ -189,7 +210,7 @@ async def search_video(
         logger.info(f"Video search request: {request.video_url}")
         
         # Execute search
-        results = await service.search_video(request.video_url, request.top_k)
+        results = await service.search_video(request.video_url, request.top_k, request.search_options())
         
         # Build response
         search_results = []
@@ -205,7 +226,9 @@ async def search_video(
                 video_url=video_url if video_url is not None else '',
                 image_text=item.get('image_text', ''),
                 video_text=item.get('video_text', ''),
-                score=item.get('score', 0.0)
+                score=item.get('score', 0.0),
+                highlights=item.get('highlights', []),
+                embeddings=item.get('embeddings')
             ))
         
         query_time = time.time() - start_time
@@ -226,6 +249,63 @@ async def search_video(
         raise handle_service_exception(e)
 
 
+@router.post("/search/similar", response_model=SearchResponse)
+async def search_similar(
+    request: SimilarSearchRequest,
+    service: SearchService = Depends(get_search_service),
+    token: Optional[str] = Depends(get_current_token)
+):
+    """
+    Similar document search interface ("more like this")
+    
+    - **id**: ID of a stored document, as returned in search results
+    - **top_k**: Return result count, default 10, maximum 100
+    """
+    start_time = time.time()
+    
+    try:
+        logger.info(f"Similar search request: {request.id}")
+        
+        # Execute search
+        results = await service.search_similar(request.id, request.top_k)
+        
+        # Build response
+        search_results = []
+        for item in results:
+            # Handle None values properly
+            image_url = item.get('image')
+            video_url = item.get('video')
+            text = item.get('text')
+            search_results.append(SearchResultItem(
+                id=item.get('id', ''),
+                text=text if text is not None else '',
+                image_url=image_url if image_url is not None else '',
+                video_url=video_url if video_url is not None else '',
+                image_text=item.get('image_text', ''),
+                video_text=item.get('video_text', ''),
+                score=item.get('score', 0.0),
+                highlights=item.get('highlights', []),
+                embeddings=item.get('embeddings')
+            ))
+        
+        query_time = time.time() - start_time
+        logger.info(f"Similar search completed, time: {query_time:.3f}s, result count: {len(search_results)}")
+        
+        return SearchResponse(
+            success=True,
+            message="Search completed",
+            total=len(search_results),
+            results=search_results,
+            query_time=query_time
+        )
+        
+    except Exception as e:
+        logger.error(f"Similar search failed: {str(e)}")
+        if not isinstance(e, MoleSearchException):
+            logger.error(traceback.format_exc())
+        raise handle_service_exception(e)
+
+
 @router.post("/search/multimodal", response_model=SearchResponse)
 async def search_multimodal(
     request: MultimodalSearchRequest,
@@ -239,6 +319,7 @@ async def search_multimodal(
     - **image_url**: Image URL (optional)
     - **video_url**: Video URL (optional)
     - **top_k**: Return result count, default 10, maximum 100
+    - **exact**, **metric**, **score_normalization**, **fields**, **include_vectors**: Optional search options
     
     Note: At least one type of input is required
     """
@@ -252,7 +333,8 @@ async def search_multimodal(
             text=request.text,
             image_url=request.image_url,
             video_url=request.video_url,
-            top_k=request.top_k
+            top_k=request.top_k,
+            options=request.search_options()
         )
         
         # Build response
@@ -269,7 +351,9 @@ async def search_multimodal(
                 video_url=video_url if video_url is not None else '',
                 image_text=item.get('image_text', ''),
                 video_text=item.get('video_text', ''),
-                score=item.get('score', 0.0)
+                score=item.get('score', 0.0),
+                highlights=item.get('highlights', []),
+                embeddings=item.get('embeddings')
             ))
         
         query_time = time.time() - start_time
@@ -311,7 +395,7 @@ async def insert_data(
         logger.info(f"Data insertion request: text={bool(request.text)}, image={bool(request.image_url)}, video={bool(request.video_url)}")
         
         # Execute insertion
-        await service.insert_data(
+        warnings = await service.insert_data(
             text=request.text,
             image_url=request.image_url,
             video_url=request.video_url
@@ -324,7 +408,8 @@ async def insert_data(
             success=True,
             message="Data insertion successful",
             inserted_count=1,
-            processing_time=processing_time
+            processing_time=processing_time,
+            warnings=warnings or []
         )
         
     except Exception as e:
@@ -409,6 +494,26 @@ async def get_status(
         )
 
 
+@router.get("/index/stats", response_model=IndexStatsResponse)
+async def get_index_stats(
+    service: SearchService = Depends(get_search_service),
+    token: Optional[str] = Depends(get_current_token)
+):
+    """
+    Get index statistics: document count, storage size and vector dimensions
+    """
+    try:
+        stats = await service.get_index_stats()
+        return IndexStatsResponse(
+            success=True,
+            message="Query successful",
+            **stats
+        )
+    except Exception as e:
+        logger.error(f"Get index stats failed: {str(e)}")
+        raise handle_service_exception(e)
+
+
 @router.post("/data/list", response_model=DataListResponse)
 async def list_data(
     request: DataListRequest,
@@ -448,6 +553,34 @@ async def list_data(
         logger.error(f"Full data paging query failed: {str(e)}")
         raise HTTPException(status_code=500, detail=f"Full data paging query failed: {str(e)}")
 
+@router.get("/data/{doc_id}", response_model=DataItemResponse)
+async def get_data(
+    doc_id: str,
+    service: SearchService = Depends(get_search_service),
+    token: Optional[str] = Depends(get_current_token)
+):
+    """
+    Single data query interface
+    - **doc_id**: Document ID as returned in search and list results
+    """
+    try:
+        item = await service.get_data(doc_id)
+        return DataItemResponse(
+            success=True,
+            message="Query successful",
+            item=DataListItem(
+                id=item['id'],
+                text=item['text'] or '',
+                image_url=item['image_url'] or '',
+                video_url=item['video_url'] or '',
+                image_text=item['image_text'] or '',
+                video_text=item['video_text'] or ''
+            )
+        )
+    except Exception as e:
+        logger.error(f"Data query failed: {str(e)}")
+        raise handle_service_exception(e)
+
 # Async data insertion endpoints
 @router.post("/data/async_insert", response_model=AsyncTaskResponse)
 async def async_insert_data(
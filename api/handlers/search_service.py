@@ -5,12 +5,11 @@ Search service - Core business logic integrating MMExtractor and Elasticsearch
 import os
 import asyncio
 from typing import List, Dict, Any, Optional
-import uuid
 
 from processor.core.pipeline import PipelineParam
 from processor.pipelines.mm_extractor import MMExtractor
 from processor.core.data import DataIO, MMData, TextItem, ImageItem, VideoItem
-from search_engine.base import SearchEngineFactory, SearchEngineParam, SearchInput, InsertData, EmbeddingInfo
+from search_engine.base import SearchEngineFactory, SearchEngineParam, SearchEngineType, SearchInput, SearchOutput, SearchOutputItem, InsertData, EmbeddingInfo, DocumentNotFoundError
 from search_engine.elasticsearch.es import ESSearchEngine
 from .models import InsertDataRequest
 from .exceptions import (
@@ -24,6 +23,52 @@ from utils.config import get_config_manager
 logger = get_logger(__name__)
 
 
+def build_insert_data(result: MMData, text: str, image_url: str, video_url: str) -> InsertData:
+    """Build the document to index from the source content and its extraction result"""
+    embeddings = []
+    image_text = ''
+    video_text = ''
+    if result.text and result.text.text_embeddings:
+        embeddings.append(EmbeddingInfo(
+            label='text_embedding',
+            embedding=result.text.text_embeddings[0]
+        ))
+
+    if result.image and result.image.image_embedding:
+        embeddings.append(EmbeddingInfo(
+            label='image_embedding',
+            embedding=result.image.image_embedding
+        ))
+        image_text = result.image.text
+        # Add image text embedding
+        if result.image.text_embeddings:
+            embeddings.append(EmbeddingInfo(
+                label='image_text_embedding',
+                embedding=result.image.text_embeddings[0]
+            ))
+
+    if result.video and result.video.video_embedding:
+        embeddings.append(EmbeddingInfo(
+            label='video_embedding',
+            embedding=result.video.video_embedding
+        ))
+        video_text = result.video.text
+        # Add video text embedding
+        if result.video.text_embeddings:
+            embeddings.append(EmbeddingInfo(
+                label='video_text_embedding',
+                embedding=result.video.text_embeddings[0]
+            ))
+    return InsertData(
+        text=text,
+        image=image_url,
+        video=video_url,
+        embeddings=embeddings,
+        image_text=image_text,
+        video_text=video_text
+    )
+
+
 class SearchService:
     """Search service class"""
     
@@ -32,10 +77,6 @@ class SearchService:
         self.search_engine = None
         self.initialized = False
 
-    def __del__(self):
-        if self.search_engine:
-            asyncio.run(self.search_engine.close())
-    
     async def initialize(self):
         """Initialize search service"""
         if self.initialized:
@@ -91,10 +132,13 @@ class SearchService:
             # Get ES configuration from configuration manager
             config_manager = get_config_manager()
             es_config = config_manager.get_elasticsearch_config()
+            engine_type = config_manager.get_search_engine_config().type.lower()
+            if engine_type == 'elasticsearch':
+                engine_type = SearchEngineType.ES
             
-            # Create ES search engine
+            # Create search engine of the configured type
             es_param = SearchEngineParam(
-                type='es',
+                type=engine_type,
                 param={
                     'host': es_config.get('host', 'localhost'),
                     'port': es_config.get('port', 9200),
@@ -102,18 +146,21 @@ class SearchService:
                     'username': es_config.get('username', ''),
                     'password': es_config.get('password', ''),
                     'scheme': es_config.get('scheme', 'http'),
+                    'slow_threshold_ms': es_config.get('slow_threshold_ms', 0),
+                    'space_type': es_config.get('space_type', 'cosinesimil'),
                     'vector_dimensions': es_config.get('vector_dimensions', {
                         'text_embedding': 1024,
                         'image_embedding': 1024,
                         'video_embedding': 1024
-                    })
+                    }),
+                    'vector_fields': es_config.get('vector_fields', {})
                 }
             )
             
             factory = SearchEngineFactory(es_param)
             self.search_engine = factory.get_search_engine()
             
-            logger.info("Search engine initialized")
+            logger.info(f"Search engine initialized: {engine_type}")
             
         except Exception as e:
             logger.error(f"Search engine initialization failed: {str(e)}")
@@ -146,7 +193,30 @@ class SearchService:
             logger.error(f"Failed to create configuration file: {str(e)}")
             raise
     
-    async def search_text(self, query: str, top_k: int = 10) -> List[Dict[str, Any]]:
+    async def _search(self, search_input: SearchInput) -> SearchOutput:
+        """Run a search, options the engine cannot honour are reported as validation errors"""
+        try:
+            return await self.search_engine.search(search_input)
+        except ValueError as e:
+            raise ValidationException(str(e))
+
+    @staticmethod
+    def _result_to_dict(item: SearchOutputItem) -> Dict[str, Any]:
+        """Convert a search result item to the handler result format, embeddings are only set when include_vectors was requested"""
+        return {
+            'id': item.id,
+            'text': item.text,
+            'image': item.image,
+            'video': item.video,
+            'image_text': item.image_text,
+            'video_text': item.video_text,
+            'score': item.score,
+            'highlights': item.highlights,
+            'embeddings': {info.label: info.embedding for info in item.embeddings} or None
+        }
+
+    async def search_text(self, query: str, top_k: int = 10,
+                          options: Optional[Dict[str, Any]] = None) -> List[Dict[str, Any]]:
         """Text search"""
         if not self.initialized:
             await self.initialize()
@@ -167,24 +237,15 @@ class SearchService:
             search_input = SearchInput(
                 text=query,
                 embeddings=embeddings,
-                topk=top_k
+                topk=top_k,
+                **(options or {})
             )
             
             # Execute search
-            search_result = await self.search_engine.search(search_input)
+            search_result = await self._search(search_input)
             
             # Convert result format
-            results = []
-            for item in search_result.items:
-                results.append({
-                    'id': str(uuid.uuid4()),  # Generate temporary ID
-                    'text': item.text,
-                    'image': item.image,
-                    'video': item.video,
-                    'image_text': item.image_text,
-                    'video_text': item.video_text,
-                    'score': item.score
-                })
+            results = [self._result_to_dict(item) for item in search_result.items]
             
             return results
             
@@ -192,7 +253,8 @@ class SearchService:
             logger.error(f"Text search failed: {str(e)}")
             raise
     
-    async def search_image(self, image_url: str, top_k: int = 10) -> List[Dict[str, Any]]:
+    async def search_image(self, image_url: str, top_k: int = 10,
+                           options: Optional[Dict[str, Any]] = None) -> List[Dict[str, Any]]:
         """Image search"""
         if not self.initialized:
             await self.initialize()
@@ -220,24 +282,15 @@ class SearchService:
             search_input = SearchInput(
                 text='',
                 embeddings=embeddings,
-                topk=top_k
+                topk=top_k,
+                **(options or {})
             )
             
             # Execute search
-            search_result = await self.search_engine.search(search_input)
+            search_result = await self._search(search_input)
             
             # Convert result format
-            results = []
-            for item in search_result.items:
-                results.append({
-                    'id': str(uuid.uuid4()),
-                    'text': item.text,
-                    'image': item.image,
-                    'video': item.video,
-                    'image_text': item.image_text,
-                    'video_text': item.video_text,
-                    'score': item.score
-                })
+            results = [self._result_to_dict(item) for item in search_result.items]
             
             return results
             
@@ -245,7 +298,8 @@ class SearchService:
             logger.error(f"Image search failed: {str(e)}")
             raise
     
-    async def search_video(self, video_url: str, top_k: int = 10) -> List[Dict[str, Any]]:
+    async def search_video(self, video_url: str, top_k: int = 10,
+                           options: Optional[Dict[str, Any]] = None) -> List[Dict[str, Any]]:
         """Video search"""
         if not self.initialized:
             await self.initialize()
@@ -273,24 +327,15 @@ class SearchService:
             search_input = SearchInput(
                 text='',
                 embeddings=embeddings,
-                topk=top_k
+                topk=top_k,
+                **(options or {})
             )
             
             # Execute search
-            search_result = await self.search_engine.search(search_input)
+            search_result = await self._search(search_input)
             
             # Convert result format
-            results = []
-            for item in search_result.items:
-                results.append({
-                    'id': str(uuid.uuid4()),
-                    'text': item.text,
-                    'image': item.image,
-                    'video': item.video,
-                    'image_text': item.image_text,
-                    'video_text': item.video_text,
-                    'score': item.score
-                })
+            results = [self._result_to_dict(item) for item in search_result.items]
             
             return results
             
@@ -298,10 +343,30 @@ class SearchService:
             logger.error(f"Video search failed: {str(e)}")
             raise
     
+    async def search_similar(self, doc_id: str, top_k: int = 10) -> List[Dict[str, Any]]:
+        """Search documents similar to a stored document"""
+        if not self.initialized:
+            await self.initialize()
+        
+        try:
+            search_result = await self.search_engine.search_similar(doc_id, top_k)
+            
+            # Convert result format
+            results = [self._result_to_dict(item) for item in search_result.items]
+            
+            return results
+            
+        except DocumentNotFoundError as e:
+            raise NotFoundException(str(e))
+        except Exception as e:
+            logger.error(f"Similar search failed: {str(e)}")
+            raise
+    
     async def search_multimodal(self, text: Optional[str] = None, 
                                image_url: Optional[str] = None,
                                video_url: Optional[str] = None,
-                               top_k: int = 10) -> List[Dict[str, Any]]:
+                               top_k: int = 10,
+                               options: Optional[Dict[str, Any]] = None) -> List[Dict[str, Any]]:
         """Multimodal search"""
         if not self.initialized:
             await self.initialize()
@@ -343,27 +408,20 @@ class SearchService:
             search_input = SearchInput(
                 text=search_text,
                 embeddings=embeddings,
-                topk=top_k
+                topk=top_k,
+                **(options or {})
             )
             
             # Execute search
-            search_result = await self.search_engine.search(search_input)
+            search_result = await self._search(search_input)
             
             # Convert result format
-            results = []
-            for item in search_result.items:
-                results.append({
-                    'id': str(uuid.uuid4()),
-                    'text': item.text,
-                    'image': item.image,
-                    'video': item.video,
-                    'image_text': item.image_text,
-                    'video_text': item.video_text,
-                    'score': item.score
-                })
+            results = [self._result_to_dict(item) for item in search_result.items]
             
             return results
             
+        except MoleSearchException:
+            raise
         except Exception as e:
             error_msg = str(e)
             logger.error(f"Multimodal search failed: {error_msg}")
@@ -380,8 +438,8 @@ class SearchService:
             else:
                 raise ServiceException(f"Multimodal search service exception: {error_msg}")
     
-    async def insert_data(self, text: str = '', image_url: str = '', video_url: str = ''):
-        """Insert single data"""
+    async def insert_data(self, text: str = '', image_url: str = '', video_url: str = '') -> List[str]:
+        """Insert single data, returning the non-fatal extraction warnings"""
         if not self.initialized:
             await self.initialize()
         
@@ -397,54 +455,15 @@ class SearchService:
             result = await self.mm_extractor.forward(mm_data)
             logger.info(f"mm_extractor result: {result}")
             
-            # Build insert data
-            embeddings = []
-            image_text = ''
-            video_text = ''
-            if result.text and result.text.text_embeddings:
-                embeddings.append(EmbeddingInfo(
-                    label='text_embedding',
-                    embedding=result.text.text_embeddings[0]
-                ))
-            
-            if result.image and result.image.image_embedding:
-                embeddings.append(EmbeddingInfo(
-                    label='image_embedding',
-                    embedding=result.image.image_embedding
-                ))
-                image_text = result.image.text
-                # Add image text embedding
-                if result.image.text_embeddings:
-                    embeddings.append(EmbeddingInfo(
-                        label='image_text_embedding',
-                        embedding=result.image.text_embeddings[0]
-                    ))
-            
-            if result.video and result.video.video_embedding:
-                embeddings.append(EmbeddingInfo(
-                    label='video_embedding',
-                    embedding=result.video.video_embedding
-                ))
-                video_text = result.video.text
-                # Add video text embedding
-                if result.video.text_embeddings:
-                    embeddings.append(EmbeddingInfo(
-                        label='video_text_embedding',
-                        embedding=result.video.text_embeddings[0]
-                    ))
-            insert_data = InsertData(
-                text=text,
-                image=image_url,
-                video=video_url,
-                embeddings=embeddings,
-                image_text=image_text,
-                video_text=video_text
-            )
+            insert_data = build_insert_data(result, text, image_url, video_url)
             
             # Execute insert
             await self.search_engine.insert(insert_data)
             
+            if result.warnings:
+                logger.warning(f"Data inserted with degraded modalities: {'; '.join(result.warnings)}")
             logger.info("Data insertion successful")
+            return result.warnings
             
         except Exception as e:
             error_msg = str(e)
@@ -480,50 +499,10 @@ class SearchService:
                 
                 # Use MMExtractor to process data
                 result = await self.mm_extractor.forward(mm_data)
+                if result.warnings:
+                    logger.warning(f"Batch item {len(insert_data_list)} inserted with degraded modalities: {'; '.join(result.warnings)}")
                 
-                # Build insert data
-                embeddings = []
-                image_text = ''
-                video_text = ''
-                if result.text and result.text.text_embeddings:
-                    embeddings.append(EmbeddingInfo(
-                        label='text_embedding',
-                        embedding=result.text.text_embeddings[0]
-                    ))
-                
-                if result.image and result.image.image_embedding:
-                    embeddings.append(EmbeddingInfo(
-                        label='image_embedding',
-                        embedding=result.image.image_embedding
-                    ))
-                    image_text = result.image.text
-                    # Add image text embedding
-                    if result.image.text_embeddings:
-                        embeddings.append(EmbeddingInfo(
-                            label='image_text_embedding',
-                            embedding=result.image.text_embeddings[0]
-                        ))
-                if result.video and result.video.video_embedding:
-                    embeddings.append(EmbeddingInfo(
-                        label='video_embedding',
-                        embedding=result.video.video_embedding
-                    ))
-                    video_text = result.video.text
-                    # Add video text embedding
-                    if result.video.text_embeddings:
-                        embeddings.append(EmbeddingInfo(
-                            label='video_text_embedding',
-                            embedding=result.video.text_embeddings[0]
-                        ))
-                insert_data = InsertData(
-                    text=data_request.text,
-                    image=data_request.image_url,
-                    video=data_request.video_url,
-                    embeddings=embeddings,
-                    image_text=image_text,
-                    video_text=video_text
-                )
-                
+                insert_data = build_insert_data(result, data_request.text, data_request.image_url, data_request.video_url)
                 insert_data_list.append(insert_data)
             
             # Execute batch insert
@@ -536,6 +515,22 @@ class SearchService:
             logger.error(f"Batch insert failed: {str(e)}")
             raise
     
+    async def get_index_stats(self) -> Dict[str, Any]:
+        """Get index statistics"""
+        if not self.initialized:
+            await self.initialize()
+        try:
+            stats = await self.search_engine.stats()
+            return {
+                'doc_count': stats.doc_count,
+                'deleted_count': stats.deleted_count,
+                'size_bytes': stats.size_bytes,
+                'vector_dims': stats.vector_dims
+            }
+        except Exception as e:
+            logger.error(f"Get index stats failed: {str(e)}")
+            raise
+    
     async def get_status(self) -> Dict[str, Any]:
         """Get service status"""
         try:
@@ -544,6 +539,8 @@ class SearchService:
                 'mm_extractor': self.mm_extractor is not None,
                 'search_engine': self.search_engine is not None
             }
+            if self.mm_extractor:
+                status['embedding_concurrency'] = self.mm_extractor.concurrency_stats()
             
             # Check search engine connection status
             if self.search_engine:
@@ -562,6 +559,39 @@ class SearchService:
             logger.error(f"Get status failed: {str(e)}")
             raise
     
+    async def close(self):
+        """Close search service and release search engine connection"""
+        if self.search_engine:
+            try:
+                await self.search_engine.close()
+                logger.info("Search engine connection closed")
+            except Exception as e:
+                logger.error(f"Search engine close failed: {str(e)}")
+            finally:
+                self.search_engine = None
+        self.mm_extractor = None
+        self.initialized = False
+    
+    async def get_data(self, doc_id: str) -> Dict[str, Any]:
+        """Get a single stored document by id"""
+        if not self.initialized:
+            await self.initialize()
+        try:
+            data = await self.search_engine.get(doc_id)
+            return {
+                'id': data.id,
+                'text': data.text,
+                'image_url': data.image,
+                'video_url': data.video,
+                'image_text': data.image_text,
+                'video_text': data.video_text
+            }
+        except DocumentNotFoundError as e:
+            raise NotFoundException(str(e))
+        except Exception as e:
+            logger.error(f"Data query failed: {str(e)}")
+            raise ServiceException(f"Data query failed: {str(e)}")
+    
     async def list_data(self, page: int = 1, page_size: int = 20) -> Dict[str, Any]:
         """Get all data with paging"""
         if not self.initialized:
@@ -574,7 +604,7 @@ class SearchService:
             items = []
             for item in result.items:
                 items.append({
-                    'id': str(uuid.uuid4()),  # Generate temporary ID
+                    'id': item.id,
                     'text': item.text,
                     'image_url': item.image,
                     'video_url': item.video,
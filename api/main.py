@@ -7,11 +7,11 @@ from fastapi import FastAPI
 from fastapi.middleware.cors import CORSMiddleware
 from fastapi.staticfiles import StaticFiles
 from contextlib import asynccontextmanager
-from handlers.search_handler import router as search_router
+from handlers.search_handler import router as search_router, close_search_service
 from handlers.file_handler import router as file_router
 from handlers.auth_handler import router as auth_router
 from handlers.api_key_handler import router as api_key_router
-from utils.logger import get_logger
+from utils.logger import get_logger, configure_logging_from_config
 from utils.config import init_config
 from utils.redis_client import init_redis
 
@@ -27,7 +27,8 @@ async def lifespan(app: FastAPI):
     logger.info("MoleSearch API starting...")
     
     # Initialize configuration
-    init_config()
+    config_manager = init_config()
+    configure_logging_from_config(config_manager.get_logging_config())
     
     # Initialize Redis connection
     if init_redis():
@@ -43,6 +44,7 @@ async def lifespan(app: FastAPI):
     yield
     
     # Execute when closing
+    await close_search_service()
     logger.info("MoleSearch API closed")
 
 # Create FastAPI application
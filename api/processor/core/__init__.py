@@ -1,3 +1,3 @@
 from .data import DataIO, MMData, TextItem, ImageItem, VideoItem, Embedding
-from .plugin import BasePluginParam, BasePlugin, get_registered_plugin_params, get_registered_plugins
+from .plugin import BasePluginParam, BasePlugin, PluginNotConfiguredError, UnsupportedPluginImplError, get_registered_plugin_params, get_registered_plugins
 from .pipeline import PipelineParam, Pipeline, get_registered_pipelines
\ No newline at end of file
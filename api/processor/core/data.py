@@ -37,6 +37,7 @@ class MMData:
     text: TextItem = field(default=None)
     image: ImageItem = field(default=None)
     video: VideoItem = field(default=None)
+    warnings: List[str] = field(default_factory=list)
 
 
 @dataclass_json
@@ -8,6 +8,21 @@ __plugins__ = {}
 __plugin_params__ = {}
 
 
+class PluginNotConfiguredError(ValueError):
+    """Raised when a plugin has no implementation configured"""
+    def __init__(self, plugin: str):
+        self.plugin = plugin
+        super().__init__(f'{plugin} is not configured: impl is empty')
+
+
+class UnsupportedPluginImplError(ValueError):
+    """Raised when a plugin implementation name is not known"""
+    def __init__(self, plugin: str, impl: str):
+        self.plugin = plugin
+        self.impl = impl
+        super().__init__(f'Unknown {plugin} implementation: {impl}')
+
+
 @dataclass_json
 @dataclass
 class BasePluginParam:
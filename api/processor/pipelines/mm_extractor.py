@@ -1,6 +1,9 @@
+import logging
 from ..core import Pipeline, PipelineParam, DataIO, MMData, TextItem, ImageItem, VideoItem
 from ..plugins import *
 
+logger = logging.getLogger(__name__)
+
 
 class MMExtractor(Pipeline):
     def __init__(self, param: PipelineParam) -> None:
@@ -11,6 +14,14 @@ class MMExtractor(Pipeline):
         self.vembed = VEmbedPlugin(param.get_plugin_param(VEmbedPluginParam.__name__))
         self.vlm = VLMPlugin(param.get_plugin_param(VLMPluginParam.__name__))
 
+    def concurrency_stats(self) -> dict:
+        """Embedding call concurrency per plugin, for status reporting"""
+        return {
+            'text_embedding': self.tembed.stats(),
+            'image_embedding': self.iembed.stats(),
+            'video_embedding': self.vembed.stats(),
+        }
+
     async def forward(self, input: MMData) -> MMData:
         output = MMData()
         output.text = TextItem() if output.text is None else output.text
@@ -32,14 +43,19 @@ class MMExtractor(Pipeline):
             embed_result = await self.iembed.forward(data_io)
             output.image.image_embedding = embed_result.embeddings[0] if embed_result.embeddings else None
             
-            # VLM generate text description
-            vlm_result = await self.vlm.forward(data_io)
-            output.image.text = vlm_result.text
-            
-            # Text embedding
-            text_data_io = DataIO(text=vlm_result.text)
-            text_embed_result = await self.tembed.forward(text_data_io)
-            output.image.text_embeddings = text_embed_result.embeddings
+            # VLM generate text description, failure only skips the caption
+            try:
+                vlm_result = await self.vlm.forward(data_io)
+                output.image.text = vlm_result.text
+                
+                # Text embedding
+                if vlm_result.text:
+                    text_data_io = DataIO(text=vlm_result.text)
+                    text_embed_result = await self.tembed.forward(text_data_io)
+                    output.image.text_embeddings = text_embed_result.embeddings
+            except Exception as e:
+                logger.warning(f'Image caption step failed, indexing without caption: {e}')
+                output.warnings.append(f'image caption: {e}')
         if input.video and input.video.video is not None:
             # Video embedding
             data_io = DataIO(
@@ -48,14 +64,19 @@ class MMExtractor(Pipeline):
             embed_result = await self.vembed.forward(data_io)
             output.video.video_embedding = embed_result.embeddings[0] if embed_result.embeddings else None
             
-            # ASR extract audio text
-            asr_result = await self.asr.forward(data_io)
-            output.video.text = asr_result.text
-            
-            # Text embedding
-            text_data_io = DataIO(text=asr_result.text)
-            text_embed_result = await self.tembed.forward(text_data_io)
-            output.video.text_embeddings = text_embed_result.embeddings
+            # ASR extract audio text, failure only skips the transcript
+            try:
+                asr_result = await self.asr.forward(data_io)
+                output.video.text = asr_result.text
+                
+                # Text embedding
+                if asr_result.text:
+                    text_data_io = DataIO(text=asr_result.text)
+                    text_embed_result = await self.tembed.forward(text_data_io)
+                    output.video.text_embeddings = text_embed_result.embeddings
+            except Exception as e:
+                logger.warning(f'Video transcript step failed, indexing without transcript: {e}')
+                output.warnings.append(f'video transcript: {e}')
         return output
     
 MMExtractor.register_self()
\ No newline at end of file
@@ -1,7 +1,7 @@
 from .aliyun import AliyunASR, AliyunASRParam
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from ...core import BasePlugin, BasePluginParam, DataIO
+from ...core import BasePlugin, BasePluginParam, DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
 from typing import Union
 
 class ImplType:
@@ -19,15 +19,16 @@ def _asr_from_dict(cls, config: dict) -> 'ASRPluginParam':
     instance.name = config.get('name', '')
     instance.type = config.get('type', '')
     instance.impl = config.get('impl', '')
+    if not instance.impl:
+        raise PluginNotConfiguredError('ASRPlugin')
+    
+    impl_type = instance.impl.lower()
+    if impl_type not in _asr_impls_:
+        raise UnsupportedPluginImplError('ASRPlugin', instance.impl)
     
     # Process nested param field
     if 'param' in config:
-        param_config = config['param']
-        impl_type = instance.impl.lower()
-        if impl_type == 'aliyun':
-            instance.param = AliyunASRParam.from_dict(param_config)
-        else:
-            raise ValueError(f'Unknown ASR implementation: {instance.impl}')
+        instance.param = _asr_impl_params_[impl_type].from_dict(config['param'])
     
     return instance
 
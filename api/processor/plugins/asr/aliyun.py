@@ -27,29 +27,24 @@ class AliyunASR(BaseASR):
 
     async def forward(self, input: DataIO) -> DataIO:
         """异步语音识别"""
-        try:
-            audio_url = AudioExtractor(
-                oss_access_key_id=self.param.oss_access_key_id,
-                oss_access_key_secret=self.param.oss_access_key_secret,
-                oss_endpoint=self.param.oss_endpoint,
-                oss_bucket_name=self.param.oss_bucket_name,
-            ).extract_audio(
-                video_url=input.video,
-                audio_prefix=self.param.audio_prefix,
-            )
-            
-            output = await AsyncDashScope.audio_recognition(
-                model=self.param.model,
-                audio_url=audio_url,
-                format='wav',
-                sample_rate=16000,
-                language_hints=['zh', 'en']
-            )
-            
-            return DataIO(
-                text=output.text if hasattr(output, 'text') else '',
-            )
-        except Exception as e:
-            # Return empty text when ASR fails, without interrupting the entire process
-            print(f'Warning: ASR processing failed, returning empty text: {e}')
-            return DataIO(text='')
+        audio_url = AudioExtractor(
+            oss_access_key_id=self.param.oss_access_key_id,
+            oss_access_key_secret=self.param.oss_access_key_secret,
+            oss_endpoint=self.param.oss_endpoint,
+            oss_bucket_name=self.param.oss_bucket_name,
+        ).extract_audio(
+            video_url=input.video,
+            audio_prefix=self.param.audio_prefix,
+        )
+        
+        output = await AsyncDashScope.audio_recognition(
+            model=self.param.model,
+            audio_url=audio_url,
+            format='wav',
+            sample_rate=16000,
+            language_hints=['zh', 'en']
+        )
+        
+        return DataIO(
+            text=output.text if hasattr(output, 'text') else '',
+        )
\ No newline at end of file
@@ -1,8 +1,8 @@
-from typing import Union
+from typing import Any, Dict, Union
 from .qwen import QwenIEmbed, QwenIEmbedParam
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from ...core import BasePlugin, BasePluginParam, DataIO
+from ...core import BasePlugin, BasePluginParam, DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
 
 class ImplType:
     QWEN = 'Qwen'.lower()
@@ -19,15 +19,16 @@ def _iembed_from_dict(cls, config: dict) -> 'IEmbedPluginParam':
     instance.name = config.get('name', '')
     instance.type = config.get('type', '')
     instance.impl = config.get('impl', '')
+    if not instance.impl:
+        raise PluginNotConfiguredError('IEmbedPlugin')
+    
+    impl_type = instance.impl.lower()
+    if impl_type not in _iembed_impls_:
+        raise UnsupportedPluginImplError('IEmbedPlugin', instance.impl)
     
     # Process nested param field
     if 'param' in config:
-        param_config = config['param']
-        impl_type = instance.impl.lower()
-        if impl_type == 'qwen':
-            instance.param = QwenIEmbedParam.from_dict(param_config)
-        else:
-            raise ValueError(f'Unknown IEmbedPlugin implementation: {instance.impl}')
+        instance.param = _iembed_impl_params_[impl_type].from_dict(config['param'])
     
     return instance
 
@@ -52,6 +53,9 @@ class IEmbedPlugin(BasePlugin):
     def forward(self, input: DataIO) -> DataIO:
         return self._impl.forward(input)
 
+    def stats(self) -> Dict[str, Any]:
+        return self._impl.stats()
+
 
 IEmbedPlugin.register_self()
 IEmbedPluginParam.register_self()
\ No newline at end of file
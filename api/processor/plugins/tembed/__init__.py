@@ -1,8 +1,8 @@
 from .qwen import QwenTEmbed, QwenTEmbedParam
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from ...core import BasePlugin, BasePluginParam, DataIO
-from typing import Union
+from ...core import BasePlugin, BasePluginParam, DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
+from typing import Any, Dict, Union
 
 class ImplType:
     QWEN = 'Qwen'.lower()
@@ -19,15 +19,16 @@ def _tembed_from_dict(cls, config: dict) -> 'TEmbedPluginParam':
     instance.name = config.get('name', '')
     instance.type = config.get('type', '')
     instance.impl = config.get('impl', '')
+    if not instance.impl:
+        raise PluginNotConfiguredError('TEmbedPlugin')
+    
+    impl_type = instance.impl.lower()
+    if impl_type not in _tembed_impls_:
+        raise UnsupportedPluginImplError('TEmbedPlugin', instance.impl)
     
     # Process nested param field
     if 'param' in config:
-        param_config = config['param']
-        impl_type = instance.impl.lower()
-        if impl_type == 'qwen':
-            instance.param = QwenTEmbedParam.from_dict(param_config)
-        else:
-            raise ValueError(f'Unknown TEmbedPlugin implementation: {instance.impl}')
+        instance.param = _tembed_impl_params_[impl_type].from_dict(config['param'])
     
     return instance
 
@@ -52,6 +53,9 @@ class TEmbedPlugin(BasePlugin):
     def forward(self, input: DataIO) -> DataIO:
         return self._impl.forward(input)
 
+    def stats(self) -> Dict[str, Any]:
+        return self._impl.stats()
+
 
 TEmbedPlugin.register_self()
 TEmbedPluginParam.register_self()
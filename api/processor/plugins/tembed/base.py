@@ -1,5 +1,6 @@
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
+from typing import Dict, Any
 from ...core import DataIO
 
 
@@ -16,4 +17,8 @@ class BaseTEmbed:
         self.param = param
 
     def forward(self, input: DataIO) -> DataIO:
-        raise NotImplementedError(f'{self.__class__.__name__} does not implement forward method')
\ No newline at end of file
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement forward method')
+
+    def stats(self) -> Dict[str, Any]:
+        """Call concurrency stats, empty when the implementation does not track them"""
+        return {}
@@ -1,9 +1,13 @@
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
 from http import HTTPStatus
+from typing import Dict, Any
 from .base import BaseTEmbed, BaseTEmbedParam
 from ...core import DataIO
 from ...utils.async_dashscope import AsyncDashScope
+from ...utils.slow_call import warn_if_slow
+from ...utils.concurrency import build_limiter
+from ...utils.dimension_check import check_dimension
 
 
 @dataclass_json
@@ -12,6 +16,12 @@ class QwenTEmbedParam(BaseTEmbedParam):
     api_key: str = field(default='')
     model: str = field(default='text-embedding-v4')
     dimension: int = field(default=1024)
+    slow_threshold_ms: int = field(default=0)
+    expected_dim: int = field(default=0)
+    max_concurrent: int = field(default=0)
+    # Adapt in-flight calls between min_concurrent and max_concurrent, backing off on 429
+    adaptive_concurrency: bool = field(default=False)
+    min_concurrent: int = field(default=1)
 
 
 @dataclass_json
@@ -19,16 +29,25 @@ class QwenTEmbedParam(BaseTEmbedParam):
 class QwenTEmbed(BaseTEmbed):
     def __init__(self, param: QwenTEmbedParam) -> None:
         super().__init__(param)
+        self._limiter = build_limiter(param.max_concurrent, param.adaptive_concurrency, param.min_concurrent)
 
     async def forward(self, input: DataIO) -> DataIO:
         """异步文本嵌入"""
-        output = await AsyncDashScope.text_embedding(
-            model=self.param.model,
-            input_text=input.text,
-            api_key=self.param.api_key,
-            dimension=self.param.dimension,
-        )
+        async with self._limiter:
+            with warn_if_slow(self.param.slow_threshold_ms, 'text embedding',
+                              model=self.param.model, input_chars=len(input.text or '')):
+                output = await AsyncDashScope.text_embedding(
+                    model=self.param.model,
+                    input_text=input.text,
+                    api_key=self.param.api_key,
+                    dimension=self.param.dimension,
+                )
         
+        embeddings = [item['embedding'] for item in output['embeddings']]
+        check_dimension('Text embedding', embeddings, self.param.expected_dim)
         return DataIO(
-            embeddings=[item['embedding'] for item in output['embeddings']],
+            embeddings=embeddings,
         )
+
+    def stats(self) -> Dict[str, Any]:
+        return self._limiter.stats()
@@ -1,8 +1,8 @@
 from .qwen import QwenVEmbed, QwenVEmbedParam
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from ...core import BasePlugin, BasePluginParam, DataIO
-from typing import Union
+from ...core import BasePlugin, BasePluginParam, DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
+from typing import Any, Dict, Union
 
 class ImplType:
     QWEN = 'Qwen'.lower()
@@ -19,15 +19,16 @@ def _vembed_from_dict(cls, config: dict) -> 'VEmbedPluginParam':
     instance.name = config.get('name', '')
     instance.type = config.get('type', '')
     instance.impl = config.get('impl', '')
+    if not instance.impl:
+        raise PluginNotConfiguredError('VEmbedPlugin')
+    
+    impl_type = instance.impl.lower()
+    if impl_type not in _vembed_impls_:
+        raise UnsupportedPluginImplError('VEmbedPlugin', instance.impl)
     
     # Process nested param field
     if 'param' in config:
-        param_config = config['param']
-        impl_type = instance.impl.lower()
-        if impl_type == 'qwen':
-            instance.param = QwenVEmbedParam.from_dict(param_config)
-        else:
-            raise ValueError(f'Unknown VEmbedPlugin implementation: {instance.impl}')
+        instance.param = _vembed_impl_params_[impl_type].from_dict(config['param'])
     
     return instance
 
@@ -52,6 +53,9 @@ class VEmbedPlugin(BasePlugin):
     def forward(self, input: DataIO) -> DataIO:
         return self._impl.forward(input)
 
+    def stats(self) -> Dict[str, Any]:
+        return self._impl.stats()
+
 
 VEmbedPlugin.register_self()
 VEmbedPluginParam.register_self()
@@ -1,9 +1,13 @@
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
 from http import HTTPStatus
+from typing import Dict, Any
 from .base import BaseVEmbed, BaseVEmbedParam
 from ...core import DataIO
-from ...utils.async_dashscope import AsyncDashScope
+from ...utils.async_dashscope import AsyncDashScope, validate_parameters
+from ...utils.slow_call import warn_if_slow
+from ...utils.concurrency import build_limiter
+from ...utils.dimension_check import check_dimension, DimensionMismatchError
 
 
 @dataclass_json
@@ -12,6 +16,14 @@ class QwenVEmbedParam(BaseVEmbedParam):
     api_key: str = field(default='')
     model: str = field(default='multimodal-embedding-v1')
     dimension: int = field(default=1024)
+    slow_threshold_ms: int = field(default=0)
+    expected_dim: int = field(default=0)
+    max_concurrent: int = field(default=0)
+    # Adapt in-flight calls between min_concurrent and max_concurrent, backing off on 429
+    adaptive_concurrency: bool = field(default=False)
+    min_concurrent: int = field(default=1)
+    # Extra DashScope request parameters, flat map of scalar values
+    parameters: Dict[str, Any] = field(default_factory=dict)
 
 
 @dataclass_json
@@ -19,24 +31,36 @@ class QwenVEmbedParam(BaseVEmbedParam):
 class QwenVEmbed(BaseVEmbed):
     def __init__(self, param: QwenVEmbedParam) -> None:
         super().__init__(param)
+        self._limiter = build_limiter(param.max_concurrent, param.adaptive_concurrency, param.min_concurrent)
+        validate_parameters(param.parameters)
 
     async def forward(self, input: DataIO) -> DataIO:
         """异步视频嵌入"""
         try:
-            output = await AsyncDashScope.multimodal_embedding(
-                model=self.param.model,
-                input_data=[{'video': input.video}],
-                api_key=self.param.api_key,
-                dimension=self.param.dimension,
-            )
+            async with self._limiter:
+                with warn_if_slow(self.param.slow_threshold_ms, 'video embedding',
+                                  model=self.param.model, input_chars=len(input.video or '')):
+                    output = await AsyncDashScope.multimodal_embedding(
+                        model=self.param.model,
+                        input_data=[{'video': input.video}],
+                        api_key=self.param.api_key,
+                        dimension=self.param.dimension,
+                        parameters=self.param.parameters,
+                    )
             
+            embeddings = [item['embedding'] for item in output['embeddings']]
+            check_dimension('Video embedding', embeddings, self.param.expected_dim)
             return DataIO(
-                embeddings=[item['embedding'] for item in output['embeddings']],
+                embeddings=embeddings,
             )
+        except DimensionMismatchError:
+            raise
         except Exception as e:
             # Improve error message, provide more context
             if "download" in str(e).lower():
                 raise Exception(f'QwenVEmbedPlugin forward failed: Video URL download error - {input.video} may be inaccessible')
             else:
                 raise Exception(f'QwenVEmbedPlugin forward failed: {str(e)}')
-    
+
+    def stats(self) -> Dict[str, Any]:
+        return self._limiter.stats()
@@ -1,7 +1,7 @@
 from .qwen import QwenVLM, QwenVLMParam
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from ...core import BasePlugin, BasePluginParam, DataIO
+from ...core import BasePlugin, BasePluginParam, DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
 from typing import Union
 
 class ImplType:
@@ -19,15 +19,16 @@ def _vlm_from_dict(cls, config: dict) -> 'VLMPluginParam':
     instance.name = config.get('name', '')
     instance.type = config.get('type', '')
     instance.impl = config.get('impl', '')
+    if not instance.impl:
+        raise PluginNotConfiguredError('VLMPlugin')
+    
+    impl_type = instance.impl.lower()
+    if impl_type not in _vlm_impls_:
+        raise UnsupportedPluginImplError('VLMPlugin', instance.impl)
     
     # Process nested param field
     if 'param' in config:
-        param_config = config['param']
-        impl_type = instance.impl.lower()
-        if impl_type == 'qwen':
-            instance.param = QwenVLMParam.from_dict(param_config)
-        else:
-            raise ValueError(f'Unknown VLMPlugin implementation: {instance.impl}')
+        instance.param = _vlm_impl_params_[impl_type].from_dict(config['param'])
     
     return instance
 
@@ -4,6 +4,50 @@ from typing import Any, Dict, List, Optional
 from http import HTTPStatus
 
 
+class DashScopeAPIError(Exception):
+    """Structured DashScope upstream error, keeps status code and error code for callers"""
+
+    def __init__(self, operation: str, status_code: int, code: str = '', message: str = '', request_id: str = ''):
+        self.operation = operation
+        self.status_code = status_code
+        self.code = code or ''
+        self.message = message or ''
+        self.request_id = request_id or ''
+        super().__init__(f'{operation} failed: {self.message}')
+
+    @classmethod
+    def from_response(cls, operation: str, rsp: Any) -> 'DashScopeAPIError':
+        """Build error from a non-OK DashScope response"""
+        return cls(
+            operation=operation,
+            status_code=getattr(rsp, 'status_code', 0),
+            code=getattr(rsp, 'code', ''),
+            message=getattr(rsp, 'message', str(rsp)),
+            request_id=getattr(rsp, 'request_id', ''),
+        )
+
+    @property
+    def is_rate_limited(self) -> bool:
+        return self.status_code == HTTPStatus.TOO_MANY_REQUESTS
+
+
+# Request arguments set by the plugin itself, not overridable through parameters
+RESERVED_PARAMETERS = {'model', 'input', 'api_key', 'dimension'}
+
+
+def validate_parameters(parameters: Dict[str, Any]) -> None:
+    """Check extra request parameters are a flat map of JSON scalar values without reserved keys"""
+    if not isinstance(parameters, dict):
+        raise ValueError(f'parameters must be a map, got {type(parameters).__name__}')
+    for key, value in parameters.items():
+        if not isinstance(key, str):
+            raise ValueError(f'parameters key must be a string, got {key!r}')
+        if key in RESERVED_PARAMETERS:
+            raise ValueError(f'parameters.{key} is reserved, set it in the plugin config instead')
+        if value is not None and not isinstance(value, (str, int, float, bool)):
+            raise ValueError(f'parameters.{key} must be a scalar value, got {type(value).__name__}')
+
+
 class AsyncDashScope:
     """Async DashScope API wrapper - use real async interface first"""
     
@@ -26,8 +70,7 @@ class AsyncDashScope:
         rsp = await asyncio.to_thread(_sync_call)
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Text embedding failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Text embedding', rsp)
         
         return rsp.output
 
@@ -36,22 +79,23 @@ class AsyncDashScope:
         model: str,
         input_data: List[Dict[str, Any]],
         api_key: str,
-        dimension: int = 1024
+        dimension: int = 1024,
+        parameters: Optional[Dict[str, Any]] = None
     ) -> Dict[str, Any]:
-        """Async multimodal embedding - use thread pool to wrap sync interface"""
+        """Async multimodal embedding - use thread pool to wrap sync interface, extra parameters are sent in the request parameters"""
         def _sync_call():
             return dashscope.MultiModalEmbedding.call(
                 model=model,
                 input=input_data,
                 api_key=api_key,
                 dimension=dimension,
+                **(parameters or {}),
             )
         
         rsp = await asyncio.to_thread(_sync_call)
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Multimodal embedding failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Multimodal embedding', rsp)
         
         return rsp.output
 
@@ -74,8 +118,7 @@ class AsyncDashScope:
         rsp = await asyncio.to_thread(_sync_call)
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Multimodal conversation failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Multimodal conversation', rsp)
         
         return rsp.output
 
@@ -103,8 +146,7 @@ class AsyncDashScope:
         rsp = await asyncio.to_thread(_sync_call)
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Audio recognition failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Audio recognition', rsp)
         
         return rsp.output
 
@@ -124,8 +166,7 @@ class AsyncDashScope:
         )
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Generation failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Generation', rsp)
         
         return rsp.output
 
@@ -148,7 +189,6 @@ class AsyncDashScope:
         rsp = await asyncio.to_thread(_sync_call)
         
         if rsp.status_code != HTTPStatus.OK:
-            error_msg = getattr(rsp, 'message', str(rsp))
-            raise Exception(f'Batch text embedding failed: {error_msg}')
+            raise DashScopeAPIError.from_response('Batch text embedding', rsp)
         
         return rsp.output 
\ No newline at end of file
@@ -0,0 +1,114 @@
+import asyncio
+import logging
+from typing import Dict, Optional
+from .async_dashscope import DashScopeAPIError
+
+logger = logging.getLogger(__name__)
+
+
+class ConcurrencyLimiter:
+    """Async context manager bounding in-flight calls to max_concurrent, disabled when max_concurrent <= 0"""
+
+    def __init__(self, max_concurrent: int) -> None:
+        self.max_concurrent = max_concurrent
+        # Created inside the running loop on first acquire, asyncio primitives made at import time
+        # bind to the wrong loop on Python 3.8/3.9
+        self._semaphore: Optional[asyncio.Semaphore] = None
+        self._loop: Optional[asyncio.AbstractEventLoop] = None
+
+    async def __aenter__(self):
+        if self.max_concurrent > 0:
+            loop = asyncio.get_running_loop()
+            if self._semaphore is None or self._loop is not loop:
+                self._semaphore = asyncio.Semaphore(self.max_concurrent)
+                self._loop = loop
+            await self._semaphore.acquire()
+        return self
+
+    async def __aexit__(self, exc_type, exc, tb):
+        if self._semaphore is not None:
+            self._semaphore.release()
+        return False
+
+    def stats(self) -> Dict[str, int]:
+        """Current concurrency limit, 0 when unlimited"""
+        return {'concurrency': max(self.max_concurrent, 0)}
+
+
+class AdaptiveConcurrencyLimiter:
+    """
+    AIMD concurrency limit for calls to a rate limited upstream
+
+    Starts at min_concurrent, adds one slot after each window of successful calls at the current
+    limit and multiplies the limit by decrease_factor when a call fails with a rate limit error.
+    Rate limit errors from calls started before the last decrease do not decrease it again.
+    """
+
+    def __init__(self, min_concurrent: int, max_concurrent: int, decrease_factor: float = 0.5) -> None:
+        if min_concurrent < 1 or max_concurrent < min_concurrent:
+            raise ValueError(f'Adaptive concurrency needs 1 <= min_concurrent <= max_concurrent, '
+                             f'got {min_concurrent} and {max_concurrent}')
+        if not 0 < decrease_factor < 1:
+            raise ValueError(f'decrease_factor must be between 0 and 1, got {decrease_factor}')
+        self.min_concurrent = min_concurrent
+        self.max_concurrent = max_concurrent
+        self.decrease_factor = decrease_factor
+        self._limit = float(min_concurrent)
+        self._in_flight = 0
+        self._rate_limited = 0
+        # Bumped on every decrease, calls remember the epoch they started in
+        self._epoch = 0
+        self._started: Dict[asyncio.Task, int] = {}
+        # Created inside the running loop, see ConcurrencyLimiter
+        self._condition: Optional[asyncio.Condition] = None
+        self._loop: Optional[asyncio.AbstractEventLoop] = None
+
+    @property
+    def concurrency(self) -> int:
+        return int(self._limit)
+
+    def _get_condition(self) -> asyncio.Condition:
+        loop = asyncio.get_running_loop()
+        if self._condition is None or self._loop is not loop:
+            self._condition = asyncio.Condition()
+            self._loop = loop
+        return self._condition
+
+    async def __aenter__(self):
+        condition = self._get_condition()
+        async with condition:
+            await condition.wait_for(lambda: self._in_flight < self.concurrency)
+            self._in_flight += 1
+            self._started[asyncio.current_task()] = self._epoch
+        return self
+
+    async def __aexit__(self, exc_type, exc, tb):
+        condition = self._get_condition()
+        async with condition:
+            self._in_flight -= 1
+            started = self._started.pop(asyncio.current_task(), self._epoch)
+            if isinstance(exc, DashScopeAPIError) and exc.is_rate_limited:
+                self._rate_limited += 1
+                if started == self._epoch:
+                    self._limit = max(float(self.min_concurrent), self._limit * self.decrease_factor)
+                    self._epoch += 1
+                    logger.info(f'Rate limited, concurrency decreased to {self.concurrency}')
+            elif exc is None:
+                self._limit = min(float(self.max_concurrent), self._limit + 1 / self._limit)
+            condition.notify_all()
+        return False
+
+    def stats(self) -> Dict[str, int]:
+        """Current concurrency limit, in-flight calls and rate limited calls so far"""
+        return {
+            'concurrency': self.concurrency,
+            'in_flight': self._in_flight,
+            'rate_limited': self._rate_limited,
+        }
+
+
+def build_limiter(max_concurrent: int, adaptive: bool = False, min_concurrent: int = 1):
+    """Fixed limiter, or an AIMD limiter between min_concurrent and max_concurrent when adaptive"""
+    if adaptive:
+        return AdaptiveConcurrencyLimiter(min_concurrent, max_concurrent)
+    return ConcurrencyLimiter(max_concurrent)
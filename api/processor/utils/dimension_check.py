@@ -0,0 +1,20 @@
+from typing import List
+
+
+class DimensionMismatchError(Exception):
+    """Embedding returned by the model does not have the configured dimension"""
+
+    def __init__(self, operation: str, expected: int, got: int) -> None:
+        self.operation = operation
+        self.expected = expected
+        self.got = got
+        super().__init__(f'{operation} dimension mismatch: expected {expected}, got {got}')
+
+
+def check_dimension(operation: str, embeddings: List[List[float]], expected_dim: int) -> None:
+    """Validate every embedding has expected_dim values, disabled when expected_dim <= 0"""
+    if expected_dim <= 0:
+        return
+    for embedding in embeddings:
+        if len(embedding) != expected_dim:
+            raise DimensionMismatchError(operation, expected_dim, len(embedding))
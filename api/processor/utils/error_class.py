@@ -0,0 +1,51 @@
+import asyncio
+from http import HTTPStatus
+from .async_dashscope import DashScopeAPIError
+from .dimension_check import DimensionMismatchError
+
+
+class ErrorClass:
+    RETRYABLE = 'retryable'
+    CLIENT_ERROR = 'client_error'
+    SERVER_ERROR = 'server_error'
+    UNKNOWN = 'unknown'
+
+
+_RETRYABLE_STATUS = {
+    HTTPStatus.REQUEST_TIMEOUT,
+    HTTPStatus.TOO_MANY_REQUESTS,
+    HTTPStatus.BAD_GATEWAY,
+    HTTPStatus.SERVICE_UNAVAILABLE,
+    HTTPStatus.GATEWAY_TIMEOUT,
+}
+
+# Upstream rejections caused by the caller's input, other 4xx (auth, quota, missing model) are our configuration
+_CLIENT_INPUT_STATUS = {
+    HTTPStatus.BAD_REQUEST,
+    HTTPStatus.REQUEST_ENTITY_TOO_LARGE,
+    HTTPStatus.UNSUPPORTED_MEDIA_TYPE,
+    HTTPStatus.UNPROCESSABLE_ENTITY,
+}
+
+# Error codes DashScope returns with 400 for account problems rather than bad input
+_ACCOUNT_ERROR_CODES = {'Arrearage', 'InvalidApiKey'}
+
+
+def classify_error(err: BaseException) -> str:
+    """Classify an inference error, following the __cause__/__context__ chain of wrapping exceptions"""
+    seen = set()
+    while err is not None and id(err) not in seen:
+        seen.add(id(err))
+        if isinstance(err, DashScopeAPIError):
+            if err.status_code in _RETRYABLE_STATUS:
+                return ErrorClass.RETRYABLE
+            if err.status_code in _CLIENT_INPUT_STATUS and err.code not in _ACCOUNT_ERROR_CODES:
+                return ErrorClass.CLIENT_ERROR
+            if err.status_code >= 400:
+                return ErrorClass.SERVER_ERROR
+        elif isinstance(err, (asyncio.TimeoutError, TimeoutError, ConnectionError)):
+            return ErrorClass.RETRYABLE
+        elif isinstance(err, DimensionMismatchError):
+            return ErrorClass.SERVER_ERROR
+        err = err.__cause__ or err.__context__
+    return ErrorClass.UNKNOWN
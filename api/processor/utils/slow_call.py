@@ -0,0 +1,22 @@
+import logging
+import time
+from contextlib import contextmanager
+
+logger = logging.getLogger(__name__)
+
+
+@contextmanager
+def warn_if_slow(threshold_ms: int, operation: str, **details):
+    """Log a warning when the wrapped call takes longer than threshold_ms, disabled when threshold_ms <= 0"""
+    if threshold_ms <= 0:
+        yield
+        return
+
+    start = time.perf_counter()
+    try:
+        yield
+    finally:
+        elapsed_ms = (time.perf_counter() - start) * 1000
+        if elapsed_ms > threshold_ms:
+            detail_str = ', '.join(f'{key}={value}' for key, value in details.items())
+            logger.warning(f'Slow {operation} call took {elapsed_ms:.0f}ms (threshold {threshold_ms}ms), {detail_str}')
@@ -4,6 +4,7 @@ Search engine module initialization
 
 from .base import BaseSearchEngine, SearchEngineFactory, SearchEngineParam
 from .elasticsearch.es import ESSearchEngine
+from .opensearch.opensearch import OpenSearchEngine
 
 # Automatically register all search engines
-__all__ = ['BaseSearchEngine', 'SearchEngineFactory', 'SearchEngineParam', 'ESSearchEngine']
+__all__ = ['BaseSearchEngine', 'SearchEngineFactory', 'SearchEngineParam', 'ESSearchEngine', 'OpenSearchEngine']
@@ -1,6 +1,8 @@
 from dataclasses import dataclass, field
 from dataclasses_json import dataclass_json
-from typing import List, Any, Dict
+from typing import List, Any, Dict, AsyncIterator, Optional, Union
+import hashlib
+import math
 
 
 _impls_ = {}
@@ -9,6 +11,26 @@ _impls_ = {}
 class SearchEngineType:
     ABSTRACT = 'abstract'
     ES = 'es'
+    OPENSEARCH = 'opensearch'
+
+# Stored payload fields that can be selected in search results, id and score are always returned
+PROJECTABLE_FIELDS = ['text', 'image', 'video', 'image_text', 'video_text']
+
+# Text fields returned with highlighted fragments on keyword queries
+HIGHLIGHT_FIELDS = ['text', 'image_text', 'video_text']
+
+
+class VectorMetric:
+    COSINE = 'cosine'
+    DOT_PRODUCT = 'dot_product'
+    L2 = 'l2'
+
+
+class ScoreNormalization:
+    NONE = 'none'
+    PER_QUERY_MAX = 'per_query_max'
+    SOFTMAX = 'softmax'
+
 
 @dataclass_json
 @dataclass
@@ -17,6 +39,33 @@ class SearchEngineParam:
     param: Dict[str, Any] = field(default_factory=dict)
 
 
+@dataclass_json
+@dataclass
+class VectorDimensions:
+    text_embedding: int = field(default=1024)
+    image_embedding: int = field(default=1024)
+    video_embedding: int = field(default=1024)
+
+
+@dataclass_json
+@dataclass
+class LuceneParam:
+    host: str = field(default='localhost')
+    port: int = field(default=9200)
+    index: str = field(default='mmretriever')
+    username: str = field(default='')
+    password: str = field(default='')
+    scheme: str = field(default='http')
+    timeout: int = field(default=30)
+    max_retries: int = field(default=3)
+    vector_dimensions: VectorDimensions = field(default_factory=VectorDimensions)
+    # Named vector fields and their dimensions, replaces the fields derived from vector_dimensions when set
+    vector_fields: Dict[str, int] = field(default_factory=dict)
+    batch_size: int = field(default=100)
+    refresh_policy: str = field(default='wait_for')
+    slow_threshold_ms: int = field(default=0)
+
+
 @dataclass_json
 @dataclass
 class EmbeddingInfo:
@@ -30,17 +79,40 @@ class SearchInput:
     text: str = field(default='')
     embeddings: List[EmbeddingInfo] = field(default_factory=list)
     topk: int = field(default=10)
+    # Exact brute-force vector scan on every engine by default, False uses the approximate ANN index instead.
+    # Exact latency grows linearly with index size, approximate search always uses the index metric.
+    exact: bool = field(default=True)
+    # Cosmetic rescaling of returned scores, ordering is unchanged (softmax also sharpens the spread)
+    score_normalization: str = field(default=ScoreNormalization.NONE)
+    # Payload fields to return, empty returns all
+    fields: List[str] = field(default_factory=list)
+    # Vector similarity override for engines that compute it at query time, None keeps the index metric
+    metric: Optional[str] = field(default=None)
+    # Return the stored vectors with each result, off by default to save bandwidth
+    include_vectors: bool = field(default=False)
+
+    def __post_init__(self):
+        if self.score_normalization not in (ScoreNormalization.NONE, ScoreNormalization.PER_QUERY_MAX, ScoreNormalization.SOFTMAX):
+            raise ValueError(f'Unsupported score_normalization: {self.score_normalization}')
+        if self.metric is not None and self.metric not in (VectorMetric.COSINE, VectorMetric.DOT_PRODUCT, VectorMetric.L2):
+            raise ValueError(f'Unsupported metric: {self.metric}')
+        unknown = [name for name in self.fields if name not in PROJECTABLE_FIELDS and name not in ('id', 'score')]
+        if unknown:
+            raise ValueError(f'Unsupported fields: {unknown}')
 
 
 @dataclass_json
 @dataclass
 class SearchOutputItem:
+    id: str = field(default='')
     text: str = field(default='')
     image: str = field(default='')
     video: str = field(default='')
     image_text: str = field(default='')
     video_text: str = field(default='')
     score: float = field(default=0.0)
+    highlights: List[str] = field(default_factory=list)
+    embeddings: List[EmbeddingInfo] = field(default_factory=list)
 
 
 @dataclass_json
@@ -52,12 +124,19 @@ class SearchOutput:
 @dataclass_json
 @dataclass
 class InsertData:
+    id: str = field(default='')
     text: str = field(default='')
     image: str = field(default='')
     video: str = field(default='')
     embeddings: List[EmbeddingInfo] = field(default_factory=list)
     image_text: str = field(default='')
     video_text: str = field(default='')
+    # Multiplier applied to this document's relevance score at search time (not to approximate ES knn clauses)
+    boost: float = field(default=1.0)
+
+    def __post_init__(self):
+        if not math.isfinite(self.boost) or self.boost < 0:
+            raise ValueError(f'boost must be a finite non-negative number, got {self.boost}')
 
 
 @dataclass_json
@@ -67,6 +146,70 @@ class ListDataOutput:
     items: List[SearchOutputItem] = field(default_factory=list)
 
 
+@dataclass_json
+@dataclass
+class IndexStats:
+    doc_count: int = field(default=0)
+    deleted_count: int = field(default=0)
+    size_bytes: int = field(default=0)
+    vector_dims: Dict[str, int] = field(default_factory=dict)
+
+
+class DocumentNotFoundError(Exception):
+    """Raised when a document id does not exist in the index"""
+    def __init__(self, doc_id: str):
+        self.doc_id = doc_id
+        super().__init__(f'Document not found: {doc_id}')
+
+
+class SearchEngineNotConfiguredError(ValueError):
+    """Raised when no search engine type is configured"""
+    def __init__(self):
+        super().__init__('Search engine is not configured: type is empty')
+
+
+class UnsupportedSearchEngineError(ValueError):
+    """Raised when the configured search engine type is not registered"""
+    def __init__(self, engine_type: str):
+        self.engine_type = engine_type
+        super().__init__(f'Unsupported search engine type: {engine_type}')
+
+
+def source_filter(fields: List[str], vector_fields: List[str], include_vectors: bool = False) -> Union[bool, List[str], Dict[str, Any]]:
+    """Build _source filter for the requested payload fields (empty requests all), vectors only when asked for"""
+    if not fields:
+        return True if include_vectors else {"excludes": vector_fields}
+    includes = [name for name in fields if name in PROJECTABLE_FIELDS]
+    if include_vectors:
+        includes += vector_fields
+    return includes or False
+
+
+def normalize_scores(items: List[SearchOutputItem], mode: str) -> List[SearchOutputItem]:
+    """Rescale result scores in place relative to the current query"""
+    if not items or mode == ScoreNormalization.NONE:
+        return items
+    max_score = max(item.score for item in items)
+    if mode == ScoreNormalization.PER_QUERY_MAX:
+        if max_score > 0:
+            for item in items:
+                item.score = item.score / max_score
+    elif mode == ScoreNormalization.SOFTMAX:
+        exps = [math.exp(item.score - max_score) for item in items]
+        total = sum(exps)
+        for item, exp in zip(items, exps):
+            item.score = exp / total
+    return items
+
+
+def derive_doc_id(data: InsertData) -> str:
+    """Get document ID, explicit id wins, otherwise derive a stable one from text and media URLs"""
+    if data.id:
+        return data.id
+    content = '\x00'.join([data.text or '', data.image or '', data.video or ''])
+    return hashlib.sha256(content.encode('utf-8')).hexdigest()
+
+
 class BaseSearchEngine(object):
     type = SearchEngineType.ABSTRACT
     def __init__(self, param: Dict[str, Any]) -> None:
@@ -81,9 +224,21 @@ class BaseSearchEngine(object):
     async def batch_insert(self, data_list: List[InsertData]) -> None:
         raise NotImplementedError(f'{self.__class__.__name__} does not implement batch_insert method')
     
+    async def get(self, doc_id: str) -> InsertData:
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement get method')
+    
+    async def search_similar(self, doc_id: str, topk: int = 10) -> SearchOutput:
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement search_similar method')
+    
     async def list_data(self, page: int = 1, page_size: int = 20) -> ListDataOutput:
         raise NotImplementedError(f'{self.__class__.__name__} does not implement list_data method')
     
+    async def stats(self) -> IndexStats:
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement stats method')
+    
+    def scan(self, batch_size: int = 500) -> AsyncIterator[List[SearchOutputItem]]:
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement scan method')
+    
     async def close(self) -> None:
         raise NotImplementedError(f'{self.__class__.__name__} does not implement close method')
     
@@ -92,9 +247,212 @@ class BaseSearchEngine(object):
         _impls_[cls.type] = cls
 
 
+class LuceneSearchEngine(BaseSearchEngine):
+    """Document layout, query parts and result parsing shared by the Elasticsearch and OpenSearch engines"""
+    type = SearchEngineType.ABSTRACT
+
+    def _vector_property(self, dims: int) -> Dict[str, Any]:
+        raise NotImplementedError(f'{self.__class__.__name__} does not implement _vector_property method')
+
+    def _index_properties(self) -> Dict[str, Any]:
+        """Build mapping properties, payload fields plus one vector field per configured embedding field"""
+        properties = {
+            "text": {
+                "type": "text",
+                "analyzer": "standard"
+            },
+            "image": {
+                "type": "keyword"
+            },
+            "video": {
+                "type": "keyword"
+            },
+            "image_text": {
+                "type": "text",
+                "analyzer": "standard"
+            },
+            "video_text": {
+                "type": "text",
+                "analyzer": "standard"
+            },
+            "boost": {
+                "type": "float"
+            }
+        }
+        for field_name, dims in self._vector_fields().items():
+            properties[field_name] = self._vector_property(dims)
+        return properties
+
+    def _vector_fields(self) -> Dict[str, int]:
+        """Get vector field names and their dimensions, declared vector_fields take precedence"""
+        if self.param.vector_fields:
+            return dict(self.param.vector_fields)
+        vector_dimensions = self.param.vector_dimensions
+        return {
+            'text_embedding': vector_dimensions.text_embedding,
+            'image_embedding': vector_dimensions.image_embedding,
+            'video_embedding': vector_dimensions.video_embedding,
+            'image_text_embedding': vector_dimensions.text_embedding,
+            'video_text_embedding': vector_dimensions.text_embedding,
+        }
+
+    def _get_embedding_field(self, label: str) -> str:
+        """Get corresponding field name based on embedding label, raise ValueError when it is not mapped"""
+        label_lower = label.lower()
+        vector_fields = self._vector_fields()
+        # Exact field names in the mapping take precedence
+        if label_lower in vector_fields:
+            return label_lower
+        if 'image_text' in label_lower or 'img_text' in label_lower:
+            field_name = 'image_text_embedding'
+        elif 'video_text' in label_lower or 'vid_text' in label_lower:
+            field_name = 'video_text_embedding'
+        elif 'text' in label_lower or 'tembed' in label_lower:
+            field_name = 'text_embedding'
+        elif 'image' in label_lower or 'img' in label_lower or 'iembed' in label_lower:
+            field_name = 'image_embedding'
+        elif 'video' in label_lower or 'vid' in label_lower or 'vembed' in label_lower:
+            field_name = 'video_embedding'
+        else:
+            field_name = None
+        if field_name not in vector_fields:
+            raise ValueError(f'Unknown embedding field: {label}, mapped fields: {sorted(vector_fields)}')
+        return field_name
+
+    def _text_query(self, text: str) -> Dict[str, Any]:
+        """Build multi_match text retrieval over text/image_text/video_text"""
+        return {
+            "multi_match": {
+                "query": text,
+                "fields": [
+                    "text^2",  # Main text weight higher
+                    "image_text",
+                    "video_text"
+                ],
+                "type": "best_fields"
+            }
+        }
+
+    def _highlight(self) -> Dict[str, Any]:
+        """Highlight keyword-matched portions, vector-only hits return none"""
+        return {
+            "fields": {field_name: {} for field_name in HIGHLIGHT_FIELDS}
+        }
+
+    def _boosted(self, query: Dict[str, Any]) -> Dict[str, Any]:
+        """Multiply relevance by the per-document boost, documents without one count as 1.0"""
+        return {
+            "function_score": {
+                "query": query,
+                "field_value_factor": {
+                    "field": "boost",
+                    "missing": 1.0
+                },
+                "boost_mode": "multiply"
+            }
+        }
+
+    def _build_doc(self, data: InsertData) -> Dict[str, Any]:
+        """Build document body from insert data"""
+        doc = {
+            "text": data.text,
+            "image": data.image,
+            "video": data.video,
+            "image_text": data.image_text,
+            "video_text": data.video_text,
+            "boost": data.boost
+        }
+
+        for embedding_info in data.embeddings:
+            if embedding_info.label and embedding_info.embedding:
+                doc[self._get_embedding_field(embedding_info.label)] = embedding_info.embedding
+
+        return doc
+
+    def _source_embeddings(self, source: Dict[str, Any]) -> List[EmbeddingInfo]:
+        """Get stored vectors from a document source, labelled with their field name"""
+        return [
+            EmbeddingInfo(label=field_name, embedding=source[field_name])
+            for field_name in self._vector_fields()
+            if source.get(field_name)
+        ]
+
+    def _source_to_data(self, doc_id: str, source: Dict[str, Any]) -> InsertData:
+        """Convert stored document source back to insert data"""
+        return InsertData(
+            id=doc_id,
+            text=source.get('text', ''),
+            image=source.get('image', ''),
+            video=source.get('video', ''),
+            image_text=source.get('image_text', ''),
+            video_text=source.get('video_text', ''),
+            boost=source.get('boost', 1.0),
+            embeddings=self._source_embeddings(source)
+        )
+
+    def _hit_to_item(self, hit: Dict[str, Any]) -> SearchOutputItem:
+        """Convert search hit to output item"""
+        source = hit.get('_source', {})
+        return SearchOutputItem(
+            id=hit.get('_id', ''),
+            text=source.get('text', ''),
+            image=source.get('image', ''),
+            video=source.get('video', ''),
+            image_text=source.get('image_text', ''),
+            video_text=source.get('video_text', ''),
+            score=hit['_score'] or 0.0,
+            highlights=[
+                fragment
+                for field_name in HIGHLIGHT_FIELDS
+                for fragment in hit.get('highlight', {}).get(field_name, [])
+            ],
+            embeddings=self._source_embeddings(source)
+        )
+
+    def _list_body(self, page: int, page_size: int) -> Dict[str, Any]:
+        """Build paged match_all search body for list_data"""
+        return {
+            "query": {"match_all": {}},
+            "from": (page - 1) * page_size,
+            "size": page_size,
+            "_source": source_filter([], list(self._vector_fields())),
+            "sort": [{"_score": {"order": "desc"}}]
+        }
+
+    def _list_output(self, response: Dict[str, Any]) -> ListDataOutput:
+        """Convert list_data search response to output"""
+        total = response['hits']['total']['value'] if isinstance(response['hits']['total'], dict) else response['hits']['total']
+        return ListDataOutput(total=total, items=[self._hit_to_item(hit) for hit in response['hits']['hits']])
+
+    def _index_stats(self, count: Dict[str, Any], index_stats: Dict[str, Any]) -> IndexStats:
+        """Convert count and docs,store index stats responses to IndexStats"""
+        primaries = index_stats['_all']['primaries']
+        return IndexStats(
+            doc_count=count['count'],
+            deleted_count=primaries['docs']['deleted'],
+            size_bytes=primaries['store']['size_in_bytes'],
+            vector_dims=self._vector_fields()
+        )
+
+    async def _batches(self, hits: AsyncIterator[Dict[str, Any]], batch_size: int) -> AsyncIterator[List[SearchOutputItem]]:
+        """Group scrolled hits into output batches of batch_size"""
+        batch = []
+        async for hit in hits:
+            batch.append(self._hit_to_item(hit))
+            if len(batch) >= batch_size:
+                yield batch
+                batch = []
+        if batch:
+            yield batch
+
+
 class SearchEngineFactory(object):
     def __init__(self, param: SearchEngineParam) -> None:
         self.param = param
 
     def get_search_engine(self) -> BaseSearchEngine:
+        if not self.param.type:
+            raise SearchEngineNotConfiguredError()
+        if self.param.type not in _impls_:
+            raise UnsupportedSearchEngineError(self.param.type)
         return _impls_[self.param.type](self.param.param)
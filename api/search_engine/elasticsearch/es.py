@@ -1,36 +1,23 @@
-from dataclasses import dataclass, field
+from dataclasses import dataclass
 from dataclasses_json import dataclass_json
-from typing import Dict, Any, List
+from typing import Dict, Any, List, AsyncIterator
 from elasticsearch import AsyncElasticsearch
-from ..base import BaseSearchEngine, SearchEngineParam, SearchEngineType, SearchInput, SearchOutput, InsertData, SearchOutputItem, EmbeddingInfo, ListDataOutput
-import uuid
+from processor.utils.slow_call import warn_if_slow
+from ..base import LuceneSearchEngine, LuceneParam, SearchEngineType, VectorMetric, SearchInput, SearchOutput, InsertData, SearchOutputItem, ListDataOutput, IndexStats, DocumentNotFoundError, derive_doc_id, normalize_scores, source_filter
 import json
 
 
-@dataclass_json
-@dataclass
-class VectorDimensions:
-    text_embedding: int = field(default=1024)
-    image_embedding: int = field(default=1024)
-    video_embedding: int = field(default=1024)
+# Similarity of the dense_vector mapping, the only metric the approximate knn path can use
+_INDEX_METRIC = VectorMetric.COSINE
+
 
 @dataclass_json
 @dataclass
-class ESParam:
-    host: str = field(default='localhost')
-    port: int = field(default=9200)
-    index: str = field(default='mmretriever')
-    username: str = field(default='')
-    password: str = field(default='')
-    scheme: str = field(default='http')
-    timeout: int = field(default=30)
-    max_retries: int = field(default=3)
-    vector_dimensions: VectorDimensions = field(default_factory=VectorDimensions)
-    batch_size: int = field(default=100)
-    refresh_policy: str = field(default='wait_for')
+class ESParam(LuceneParam):
+    pass
 
 
-class ESSearchEngine(BaseSearchEngine):
+class ESSearchEngine(LuceneSearchEngine):
     type = SearchEngineType.ES
 
     def __init__(self, param: Dict[str, Any]) -> None:
@@ -60,61 +47,21 @@ class ESSearchEngine(BaseSearchEngine):
         if not await self.es.indices.exists(index=self.index_name):
             mapping = {
                 "mappings": {
-                    "properties": {
-                        "text": {
-                            "type": "text",
-                            "analyzer": "standard"
-                        },
-                        "image": {
-                            "type": "keyword"
-                        },
-                        "video": {
-                            "type": "keyword"
-                        },
-                        "image_text": {
-                            "type": "text",
-                            "analyzer": "standard"
-                        },
-                        "video_text": {
-                            "type": "text",
-                            "analyzer": "standard"
-                        },
-                        "text_embedding": {
-                            "type": "dense_vector",
-                            "dims": self.vector_dimensions.text_embedding,
-                            "index": True,
-                            "similarity": "cosine"
-                        },
-                        "image_embedding": {
-                            "type": "dense_vector",
-                            "dims": self.vector_dimensions.image_embedding,
-                            "index": True,
-                            "similarity": "cosine"
-                        },
-                        "video_embedding": {
-                            "type": "dense_vector",
-                            "dims": self.vector_dimensions.video_embedding,
-                            "index": True,
-                            "similarity": "cosine"
-                        },
-                        "image_text_embedding": {
-                            "type": "dense_vector",
-                            "dims": self.vector_dimensions.text_embedding,
-                            "index": True,
-                            "similarity": "cosine"
-                        },
-                        "video_text_embedding": {
-                            "type": "dense_vector",
-                            "dims": self.vector_dimensions.text_embedding,
-                            "index": True,
-                            "similarity": "cosine"
-                        }
-                    }
+                    "properties": self._index_properties()
                 }
             }
             
             await self.es.indices.create(index=self.index_name, **mapping)
 
+    def _vector_property(self, dims: int) -> Dict[str, Any]:
+        """Build dense_vector mapping for one embedding field"""
+        return {
+            "type": "dense_vector",
+            "dims": dims,
+            "index": True,
+            "similarity": _INDEX_METRIC
+        }
+
     async def search(self, input: SearchInput) -> SearchOutput:
         """Execute search, support text retrieval and vector retrieval mixed retrieval, unified sorting"""
         await self._ensure_index()
@@ -123,38 +70,24 @@ class ESSearchEngine(BaseSearchEngine):
         
         # Build multi_match text retrieval (support text/image_text/video_text)
         if input.text:
-            should_queries.append({
-                "multi_match": {
-                    "query": input.text,
-                    "fields": [
-                        "text^2",  # Main text weight higher
-                        "image_text",
-                        "video_text"
-                    ],
-                    "type": "best_fields"
-                }
-            })
+            should_queries.append(self._text_query(input.text))
         
         # Build vector retrieval (support multiple embedding fields)
+        # Exact uses a script_score scan, approximate uses the HNSW index via top-level knn
+        if input.metric and input.metric != _INDEX_METRIC and not input.exact:
+            raise ValueError('ES approximate knn uses the index similarity, metric override requires exact search')
+        knn_queries = []
         for embedding_info in input.embeddings:
             if embedding_info.label and embedding_info.embedding:
                 field_name = self._get_embedding_field(embedding_info.label)
-                if field_name:
-                    should_queries.append({
-                        "script_score": {
-                            "query": {"match_all": {}},
-                            "script": {
-                                "source": f"cosineSimilarity(params.query_vector, '{field_name}') + 1.0",
-                                "params": {
-                                    "query_vector": embedding_info.embedding
-                                }
-                            }
-                        }
-                    })
+                if input.exact:
+                    should_queries.append(self._vector_query(field_name, embedding_info.embedding, input.metric or _INDEX_METRIC))
+                else:
+                    knn_queries.append(self._knn_query(field_name, embedding_info.embedding, input.topk))
         
         # Build final query
         if not should_queries:
-            query = {"match_all": {}}
+            query = None if knn_queries else {"match_all": {}}
         elif len(should_queries) == 1:
             query = should_queries[0]
         else:
@@ -168,59 +101,117 @@ class ESSearchEngine(BaseSearchEngine):
         # Execute search
         try:
             search_body = {
-                "query": query,
                 "size": input.topk,
-                "_source": True
+                "_source": source_filter(input.fields, list(self._vector_fields()), input.include_vectors)
             }
+            if query is not None:
+                search_body["query"] = self._boosted(query)
+            if knn_queries:
+                search_body["knn"] = knn_queries
+            if input.text:
+                search_body["highlight"] = self._highlight()
             
-            response = await self.es.search(
-                index=self.index_name,
-                **search_body
-            )
+            with warn_if_slow(self.param.slow_threshold_ms, 'ES search',
+                              index=self.index_name, query_chars=len(input.text), vectors=len(input.embeddings)):
+                response = await self.es.search(
+                    index=self.index_name,
+                    **search_body
+                )
             
             # Parse result
-            items = []
-            for hit in response['hits']['hits']:
-                source = hit['_source']
-                item = SearchOutputItem(
-                    text=source.get('text', ''),
-                    image=source.get('image', ''),
-                    video=source.get('video', ''),
-                    image_text=source.get('image_text', ''),
-                    video_text=source.get('video_text', ''),
-                    score=hit['_score']
-                )
-                items.append(item)
+            items = [self._hit_to_item(hit) for hit in response['hits']['hits']]
             
-            return SearchOutput(items=items)
+            return SearchOutput(items=normalize_scores(items, input.score_normalization))
             
         except Exception as e:
             print(f"ES search error: {e}")
             return SearchOutput(items=[])
 
+    async def get(self, doc_id: str) -> InsertData:
+        """Fetch a stored document with its vectors by id"""
+        await self._ensure_index()
+        
+        response = await self.es.options(ignore_status=404).get(index=self.index_name, id=doc_id)
+        if not response.get('found'):
+            raise DocumentNotFoundError(doc_id)
+        
+        return self._source_to_data(doc_id, response['_source'])
+
+    async def search_similar(self, doc_id: str, topk: int = 10) -> SearchOutput:
+        """Search documents nearest to a stored document by exact scoring on all of its stored vectors"""
+        doc = await self.get(doc_id)
+        
+        should_queries = [
+            self._vector_query(embedding_info.label, embedding_info.embedding)
+            for embedding_info in doc.embeddings
+        ]
+        if not should_queries:
+            return SearchOutput(items=[])
+        
+        search_body = {
+            "query": self._boosted({
+                "bool": {
+                    "should": should_queries,
+                    "minimum_should_match": 1,
+                    # Exclude the document itself
+                    "must_not": [{"ids": {"values": [doc_id]}}]
+                }
+            }),
+            "size": topk,
+            "_source": source_filter([], list(self._vector_fields()))
+        }
+        
+        with warn_if_slow(self.param.slow_threshold_ms, 'ES similar search',
+                          index=self.index_name, vectors=len(should_queries)):
+            response = await self.es.search(
+                index=self.index_name,
+                **search_body
+            )
+        
+        return SearchOutput(items=[self._hit_to_item(hit) for hit in response['hits']['hits']])
+
+    def _vector_query(self, field_name: str, vector: List[float], metric: str = VectorMetric.COSINE) -> Dict[str, Any]:
+        """Build script_score query on a vector field, scores are shifted to stay non-negative
+
+        Only documents that have the field are scored, the vector functions fail on documents without it
+        """
+        if metric == VectorMetric.DOT_PRODUCT:
+            source = f"double s = dotProduct(params.query_vector, '{field_name}'); return s < 0 ? 1 / (1 - s) : s + 1;"
+        elif metric == VectorMetric.L2:
+            source = f"1 / (1 + l2norm(params.query_vector, '{field_name}'))"
+        else:
+            source = f"cosineSimilarity(params.query_vector, '{field_name}') + 1.0"
+        return {
+            "script_score": {
+                "query": {"exists": {"field": field_name}},
+                "script": {
+                    "source": source,
+                    "params": {
+                        "query_vector": vector
+                    }
+                }
+            }
+        }
+
+    def _knn_query(self, field_name: str, vector: List[float], k: int) -> Dict[str, Any]:
+        """Build approximate k-NN clause on a vector field"""
+        return {
+            "field": field_name,
+            "query_vector": vector,
+            "k": k,
+            "num_candidates": min(max(k * 10, 100), 10000)
+        }
+
     async def insert(self, data: InsertData) -> None:
         """Insert data into ES"""
         await self._ensure_index()
         
         try:
             # Build document
-            doc = {
-                "text": data.text,
-                "image": data.image,
-                "video": data.video,
-                "image_text": data.image_text,
-                "video_text": data.video_text
-            }
-            
-            # Add embedding data
-            for embedding_info in data.embeddings:
-                if embedding_info.label and embedding_info.embedding:
-                    field_name = self._get_embedding_field(embedding_info.label)
-                    if field_name:
-                        doc[field_name] = embedding_info.embedding
+            doc = self._build_doc(data)
             
-            # Generate document ID
-            doc_id = str(uuid.uuid4())
+            # Derive stable document ID so re-inserting the same content overwrites it
+            doc_id = derive_doc_id(data)
             
             # Insert document
             await self.es.index(
@@ -236,23 +227,6 @@ class ESSearchEngine(BaseSearchEngine):
             print(f"ES insert error: {e}")
             raise
 
-    def _get_embedding_field(self, label: str) -> str:
-        """Get corresponding field name based on embedding label"""
-        label_lower = label.lower()
-        if 'text' in label_lower or 'tembed' in label_lower:
-            return 'text_embedding'
-        elif 'image' in label_lower or 'img' in label_lower or 'iembed' in label_lower:
-            return 'image_embedding'
-        elif 'video' in label_lower or 'vid' in label_lower or 'vembed' in label_lower:
-            return 'video_embedding'
-        elif 'image_text' in label_lower or 'img_text' in label_lower:
-            return 'image_text_embedding'
-        elif 'video_text' in label_lower or 'vid_text' in label_lower:
-            return 'video_text_embedding'
-        else:
-            # Default return text embedding field
-            return 'text_embedding'
-
     async def batch_insert(self, data_list: List[InsertData]) -> None:
         """Batch insert data with configurable batch size"""
         await self._ensure_index()
@@ -261,30 +235,14 @@ class ESSearchEngine(BaseSearchEngine):
             # Process data in batches according to batch_size
             for i in range(0, len(data_list), self.param.batch_size):
                 batch_data = data_list[i:i + self.param.batch_size]
-                actions = []
-                
-                for data in batch_data:
-                    doc = {
-                        "text": data.text,
-                        "image": data.image,
-                        "video": data.video,
-                        "image_text": data.image_text,
-                        "video_text": data.video_text
-                    }
-                    
-                    # Add embedding data
-                    for embedding_info in data.embeddings:
-                        if embedding_info.label and embedding_info.embedding:
-                            field_name = self._get_embedding_field(embedding_info.label)
-                            if field_name:
-                                doc[field_name] = embedding_info.embedding
-                    
-                    action = {
+                actions = [
+                    {
                         "_index": self.index_name,
-                        "_id": str(uuid.uuid4()),
-                        "_source": doc
+                        "_id": derive_doc_id(data),
+                        "_source": self._build_doc(data)
                     }
-                    actions.append(action)
+                    for data in batch_data
+                ]
                 
                 # Batch insert current batch
                 from elasticsearch.helpers import async_bulk
@@ -323,47 +281,40 @@ class ESSearchEngine(BaseSearchEngine):
         await self._ensure_index()
         
         try:
-            # Calculate paging parameters
-            from_index = (page - 1) * page_size
-            
-            # Build query
-            search_body = {
-                "query": {"match_all": {}},
-                "from": from_index,
-                "size": page_size,
-                "_source": True,
-                "sort": [{"_score": {"order": "desc"}}]  # Sort by score in descending order
-            }
-            
-            # Execute search
             response = await self.es.search(
                 index=self.index_name,
-                **search_body
+                **self._list_body(page, page_size)
             )
             
-            # Get total
-            total = response['hits']['total']['value'] if isinstance(response['hits']['total'], dict) else response['hits']['total']
-            
-            # Parse result
-            items = []
-            for hit in response['hits']['hits']:
-                source = hit['_source']
-                item = SearchOutputItem(
-                    text=source.get('text', ''),
-                    image=source.get('image', ''),
-                    video=source.get('video', ''),
-                    image_text=source.get('image_text', ''),
-                    video_text=source.get('video_text', ''),
-                    score=hit['_score']
-                )
-                items.append(item)
-            
-            return ListDataOutput(total=total, items=items)
+            return self._list_output(response)
             
         except Exception as e:
             print(f"ES query data error: {e}")
             return ListDataOutput(total=0, items=[])
 
+    async def stats(self) -> IndexStats:
+        """Get document count, storage size and vector dimensions of the index"""
+        await self._ensure_index()
+        
+        count = await self.es.count(index=self.index_name)
+        index_stats = await self.es.indices.stats(index=self.index_name, metric='docs,store')
+        
+        return self._index_stats(count, index_stats)
+
+    async def scan(self, batch_size: int = 500) -> AsyncIterator[List[SearchOutputItem]]:
+        """Iterate over every document in batches, backed by the scroll API so memory stays bounded"""
+        await self._ensure_index()
+
+        from elasticsearch.helpers import async_scan
+        hits = async_scan(
+            self.es,
+            index=self.index_name,
+            query={"query": {"match_all": {}}},
+            size=batch_size
+        )
+        async for batch in self._batches(hits, batch_size):
+            yield batch
+
     async def close(self):
         """Close the ES connection"""
         await self.es.close()
@@ -0,0 +1,310 @@
+import logging
+from dataclasses import dataclass, field
+from dataclasses_json import dataclass_json
+from typing import Dict, Any, List, AsyncIterator
+from opensearchpy import AsyncOpenSearch, NotFoundError
+from processor.utils.slow_call import warn_if_slow
+from ..base import LuceneSearchEngine, LuceneParam, SearchEngineType, VectorMetric, SearchInput, SearchOutput, InsertData, SearchOutputItem, ListDataOutput, IndexStats, DocumentNotFoundError, derive_doc_id, normalize_scores, source_filter
+
+logger = logging.getLogger(__name__)
+
+
+class SpaceType:
+    L2 = 'l2'
+    COSINE = 'cosinesimil'
+    INNER_PRODUCT = 'innerproduct'
+
+
+_METRIC_SPACE_TYPES = {
+    VectorMetric.COSINE: SpaceType.COSINE,
+    VectorMetric.DOT_PRODUCT: SpaceType.INNER_PRODUCT,
+    VectorMetric.L2: SpaceType.L2,
+}
+
+
+@dataclass_json
+@dataclass
+class OpenSearchParam(LuceneParam):
+    space_type: str = field(default=SpaceType.COSINE)
+
+
+class OpenSearchEngine(LuceneSearchEngine):
+    type = SearchEngineType.OPENSEARCH
+
+    def __init__(self, param: Dict[str, Any]) -> None:
+        self.param = OpenSearchParam().from_dict(param)
+        if self.param.space_type not in (SpaceType.L2, SpaceType.COSINE, SpaceType.INNER_PRODUCT):
+            raise ValueError(f'Unsupported OpenSearch space_type: {self.param.space_type}')
+
+        # Build OpenSearch connection
+        os_config = {
+            'hosts': [f"{self.param.scheme}://{self.param.host}:{self.param.port}"],
+            'max_retries': self.param.max_retries,
+            'retry_on_timeout': True,
+            'timeout': self.param.timeout,
+            'verify_certs': False
+        }
+
+        if self.param.username and self.param.password:
+            os_config['http_auth'] = (self.param.username, self.param.password)
+
+        self.client = AsyncOpenSearch(**os_config)
+        self.index_name = self.param.index
+        self.vector_dimensions = self.param.vector_dimensions
+
+    async def _ensure_index(self):
+        """Ensure index exists with k-NN settings and knn_vector mapping"""
+        if not await self.client.indices.exists(index=self.index_name):
+            body = {
+                "settings": {
+                    "index": {
+                        "knn": True
+                    }
+                },
+                "mappings": {
+                    "properties": self._index_properties()
+                }
+            }
+
+            await self.client.indices.create(index=self.index_name, body=body)
+
+    def _vector_property(self, dims: int) -> Dict[str, Any]:
+        """Build knn_vector mapping for one embedding field"""
+        return {
+            "type": "knn_vector",
+            "dimension": dims,
+            "method": {
+                "name": "hnsw",
+                "space_type": self.param.space_type,
+                "engine": "lucene"
+            }
+        }
+
+    async def search(self, input: SearchInput) -> SearchOutput:
+        """Execute search, text retrieval and k-NN retrieval are combined in one bool query"""
+        await self._ensure_index()
+
+        should_queries = []
+
+        # Build multi_match text retrieval (support text/image_text/video_text)
+        if input.text:
+            should_queries.append(self._text_query(input.text))
+
+        # Build k-NN retrieval (support multiple embedding fields)
+        # Exact uses a brute-force knn_score script, approximate uses the HNSW index
+        space_type = _METRIC_SPACE_TYPES[input.metric] if input.metric else self.param.space_type
+        if space_type != self.param.space_type and not input.exact:
+            raise ValueError('OpenSearch approximate knn uses the index space_type, metric override requires exact search')
+        for embedding_info in input.embeddings:
+            if embedding_info.label and embedding_info.embedding:
+                field_name = self._get_embedding_field(embedding_info.label)
+                if input.exact:
+                    should_queries.append(self._exact_vector_query(field_name, embedding_info.embedding, space_type))
+                else:
+                    should_queries.append(self._vector_query(field_name, embedding_info.embedding, input.topk))
+
+        # Build final query
+        if not should_queries:
+            query = {"match_all": {}}
+        elif len(should_queries) == 1:
+            query = should_queries[0]
+        else:
+            query = {
+                "bool": {
+                    "should": should_queries,
+                    "minimum_should_match": 1
+                }
+            }
+
+        try:
+            body = {
+                "query": self._boosted(query),
+                "size": input.topk,
+                "_source": source_filter(input.fields, list(self._vector_fields()), input.include_vectors)
+            }
+            if input.text:
+                body["highlight"] = self._highlight()
+
+            with warn_if_slow(self.param.slow_threshold_ms, 'OpenSearch search',
+                              index=self.index_name, query_chars=len(input.text), vectors=len(input.embeddings)):
+                response = await self.client.search(index=self.index_name, body=body)
+
+            items = [self._hit_to_item(hit) for hit in response['hits']['hits']]
+
+            return SearchOutput(items=normalize_scores(items, input.score_normalization))
+
+        except Exception as e:
+            logger.error(f"OpenSearch search error: {e}")
+            return SearchOutput(items=[])
+
+    async def get(self, doc_id: str) -> InsertData:
+        """Fetch a stored document with its vectors by id"""
+        await self._ensure_index()
+
+        try:
+            response = await self.client.get(index=self.index_name, id=doc_id)
+        except NotFoundError:
+            raise DocumentNotFoundError(doc_id)
+
+        return self._source_to_data(doc_id, response['_source'])
+
+    async def search_similar(self, doc_id: str, topk: int = 10) -> SearchOutput:
+        """Search documents nearest to a stored document by exact scoring on all of its stored vectors"""
+        doc = await self.get(doc_id)
+
+        should_queries = [
+            self._exact_vector_query(embedding_info.label, embedding_info.embedding, self.param.space_type)
+            for embedding_info in doc.embeddings
+        ]
+        if not should_queries:
+            return SearchOutput(items=[])
+
+        body = {
+            "query": self._boosted({
+                "bool": {
+                    "should": should_queries,
+                    "minimum_should_match": 1,
+                    "must_not": [{"ids": {"values": [doc_id]}}]
+                }
+            }),
+            "size": topk,
+            "_source": source_filter([], list(self._vector_fields()))
+        }
+
+        with warn_if_slow(self.param.slow_threshold_ms, 'OpenSearch similar search',
+                          index=self.index_name, vectors=len(should_queries)):
+            response = await self.client.search(index=self.index_name, body=body)
+
+        return SearchOutput(items=[self._hit_to_item(hit) for hit in response['hits']['hits']])
+
+    async def insert(self, data: InsertData) -> None:
+        """Insert data into OpenSearch"""
+        await self._ensure_index()
+
+        try:
+            await self.client.index(
+                index=self.index_name,
+                id=derive_doc_id(data),
+                body=self._build_doc(data),
+                refresh=True
+            )
+        except Exception as e:
+            logger.error(f"OpenSearch insert error: {e}")
+            raise
+
+    async def batch_insert(self, data_list: List[InsertData]) -> None:
+        """Batch insert data with configurable batch size"""
+        await self._ensure_index()
+
+        try:
+            from opensearchpy.helpers import async_bulk
+
+            for i in range(0, len(data_list), self.param.batch_size):
+                batch_data = data_list[i:i + self.param.batch_size]
+                actions = [
+                    {
+                        "_index": self.index_name,
+                        "_id": derive_doc_id(data),
+                        "_source": self._build_doc(data)
+                    }
+                    for data in batch_data
+                ]
+
+                await async_bulk(
+                    self.client,
+                    actions,
+                    chunk_size=self.param.batch_size,
+                    refresh=self.param.refresh_policy
+                )
+
+            # Final refresh if not using wait_for policy
+            if self.param.refresh_policy != 'wait_for':
+                await self.client.indices.refresh(index=self.index_name)
+
+        except Exception as e:
+            logger.error(f"OpenSearch batch insert error: {e}")
+            raise
+
+    async def delete_all(self) -> None:
+        """Delete all data in the index"""
+        try:
+            if await self.client.indices.exists(index=self.index_name):
+                await self.client.delete_by_query(
+                    index=self.index_name,
+                    body={"query": {"match_all": {}}}
+                )
+                await self.client.indices.refresh(index=self.index_name)
+        except Exception as e:
+            # Don't raise exception for delete_all, just log it
+            logger.error(f"OpenSearch delete data error: {e}")
+
+    async def list_data(self, page: int = 1, page_size: int = 20) -> ListDataOutput:
+        """Query all data with paging"""
+        await self._ensure_index()
+
+        try:
+            response = await self.client.search(index=self.index_name, body=self._list_body(page, page_size))
+
+            return self._list_output(response)
+
+        except Exception as e:
+            logger.error(f"OpenSearch query data error: {e}")
+            return ListDataOutput(total=0, items=[])
+
+    async def stats(self) -> IndexStats:
+        """Get document count, storage size and vector dimensions of the index"""
+        await self._ensure_index()
+
+        count = await self.client.count(index=self.index_name)
+        index_stats = await self.client.indices.stats(index=self.index_name, metric='docs,store')
+
+        return self._index_stats(count, index_stats)
+
+    async def scan(self, batch_size: int = 500) -> AsyncIterator[List[SearchOutputItem]]:
+        """Iterate over every document in batches, backed by the scroll API so memory stays bounded"""
+        await self._ensure_index()
+
+        from opensearchpy.helpers import async_scan
+        hits = async_scan(
+            self.client,
+            index=self.index_name,
+            query={"query": {"match_all": {}}},
+            size=batch_size
+        )
+        async for batch in self._batches(hits, batch_size):
+            yield batch
+
+    async def close(self):
+        """Close the OpenSearch connection"""
+        await self.client.close()
+
+    def _vector_query(self, field_name: str, vector: List[float], k: int) -> Dict[str, Any]:
+        """Build k-NN query on a vector field"""
+        return {
+            "knn": {
+                field_name: {
+                    "vector": vector,
+                    "k": k
+                }
+            }
+        }
+
+    def _exact_vector_query(self, field_name: str, vector: List[float], space_type: str) -> Dict[str, Any]:
+        """Build exact brute-force k-NN query on a vector field, only documents that have the field are scored"""
+        return {
+            "script_score": {
+                "query": {"exists": {"field": field_name}},
+                "script": {
+                    "lang": "knn",
+                    "source": "knn_score",
+                    "params": {
+                        "field": field_name,
+                        "query_value": vector,
+                        "space_type": space_type
+                    }
+                }
+            }
+        }
+
+
+OpenSearchEngine.register_self()
@@ -9,6 +9,8 @@ echo "🚀 Starting MoleSearch API and Worker..."
 cleanup() {
     echo "🛑 Stopping all processes..."
     kill $API_PID $WORKER_PID 2>/dev/null
+    # Let the worker finish or requeue its in-flight tasks
+    wait $WORKER_PID 2>/dev/null
     exit 0
 }
 
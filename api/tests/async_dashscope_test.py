@@ -0,0 +1,88 @@
+#!/usr/bin/env python3
+"""
+AsyncDashScope test file
+Test error handling of the DashScope wrapper with mocked SDK responses
+"""
+import unittest
+import asyncio
+import os
+import sys
+from http import HTTPStatus
+from unittest.mock import Mock, patch
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from processor.utils.async_dashscope import AsyncDashScope, DashScopeAPIError
+from processor.utils.error_class import classify_error, ErrorClass
+
+
+class TestAsyncDashScope(unittest.TestCase):
+    """AsyncDashScope test class"""
+
+    def test_01_rate_limit_error(self):
+        """Test 429 response is surfaced as structured DashScopeAPIError"""
+        rsp = Mock(
+            status_code=HTTPStatus.TOO_MANY_REQUESTS,
+            code='Throttling.RateQuota',
+            message='Requests rate limit exceeded',
+            request_id='req-429',
+        )
+        with patch('processor.utils.async_dashscope.dashscope.TextEmbedding.call', return_value=rsp):
+            with self.assertRaises(DashScopeAPIError) as ctx:
+                asyncio.run(AsyncDashScope.text_embedding(
+                    model='text-embedding-v4',
+                    input_text='test',
+                    api_key='test_key',
+                ))
+
+        err = ctx.exception
+        self.assertEqual(err.status_code, HTTPStatus.TOO_MANY_REQUESTS)
+        self.assertEqual(err.code, 'Throttling.RateQuota')
+        self.assertEqual(err.request_id, 'req-429')
+        self.assertTrue(err.is_rate_limited)
+        self.assertEqual(str(err), 'Text embedding failed: Requests rate limit exceeded')
+
+
+
+class TestClassifyError(unittest.TestCase):
+    """classify_error test class"""
+
+    def test_01_rate_limit_retryable(self):
+        """Test 429 is retryable"""
+        err = DashScopeAPIError('Text embedding', HTTPStatus.TOO_MANY_REQUESTS)
+        self.assertEqual(classify_error(err), ErrorClass.RETRYABLE)
+
+    def test_02_bad_request_client_error(self):
+        """Test 400 is a client error"""
+        err = DashScopeAPIError('Text embedding', HTTPStatus.BAD_REQUEST, code='InvalidParameter')
+        self.assertEqual(classify_error(err), ErrorClass.CLIENT_ERROR)
+
+    def test_03_timeout_retryable(self):
+        """Test network timeout is retryable"""
+        self.assertEqual(classify_error(asyncio.TimeoutError()), ErrorClass.RETRYABLE)
+
+    def test_04_wrapped_error(self):
+        """Test the classification of a wrapped error follows the exception chain"""
+        try:
+            try:
+                raise DashScopeAPIError('Multimodal embedding', HTTPStatus.INTERNAL_SERVER_ERROR)
+            except Exception as e:
+                raise Exception(f'QwenVEmbedPlugin forward failed: {e}')
+        except Exception as wrapped:
+            self.assertEqual(classify_error(wrapped), ErrorClass.SERVER_ERROR)
+
+        self.assertEqual(classify_error(ValueError('boom')), ErrorClass.UNKNOWN)
+
+    def test_05_auth_error_server_error(self):
+        """Test upstream 401/403 and account errors are our misconfiguration, not a client error"""
+        for status, code in [(HTTPStatus.UNAUTHORIZED, 'InvalidApiKey'),
+                             (HTTPStatus.FORBIDDEN, 'AccessDenied'),
+                             (HTTPStatus.BAD_REQUEST, 'Arrearage')]:
+            with self.subTest(status=status, code=code):
+                err = DashScopeAPIError('Text embedding', status, code=code)
+                self.assertEqual(classify_error(err), ErrorClass.SERVER_ERROR)
+
+
+if __name__ == '__main__':
+    unittest.main()
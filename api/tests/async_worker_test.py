@@ -0,0 +1,85 @@
+#!/usr/bin/env python3
+"""
+Async worker test file
+Test task results and shutdown drain of the worker
+"""
+import unittest
+import asyncio
+import os
+import sys
+from unittest.mock import AsyncMock, MagicMock
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.config import init_config
+
+init_config(os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), 'config.template.yaml'))
+
+from workers.async_worker import AsyncWorker
+
+
+class TestAsyncWorker(unittest.TestCase):
+    """Async worker test class"""
+
+    def _worker(self) -> AsyncWorker:
+        worker = AsyncWorker()
+        worker.task_manager = MagicMock()
+        return worker
+
+    def test_01_insert_result_warnings(self):
+        """Test extraction warnings from the service end up in the task result"""
+        worker = self._worker()
+        worker.search_service = MagicMock()
+        worker.search_service.insert_data = AsyncMock(return_value=['video transcript: ASR unavailable'])
+
+        asyncio.run(worker._process_insert_data('task-1', {'video_url': 'https://example.com/a.mp4'}))
+
+        result = worker.task_manager.update_task_status.call_args.kwargs['result']
+        self.assertEqual(result['warnings'], ['video transcript: ASR unavailable'])
+
+    def test_02_shutdown_drains_in_flight(self):
+        """Test stop during a batch returns from run and shutdown waits for the running tasks"""
+        worker = self._worker()
+        worker.task_manager.get_pending_tasks.return_value = [{'task_id': str(i)} for i in range(3)]
+        processed = []
+
+        async def process_task(task_info):
+            worker.stop()
+            await asyncio.sleep(0.05)
+            processed.append(task_info['task_id'])
+
+        worker.process_task = process_task
+
+        async def run_and_drain():
+            await worker.run(check_interval=0)
+            self.assertEqual(len(worker.in_flight), 3)
+            return await worker.shutdown(timeout=5)
+
+        self.assertEqual(asyncio.run(run_and_drain()), 0)
+        self.assertEqual(sorted(processed), ['0', '1', '2'])
+        self.assertEqual(worker.in_flight, {})
+        worker.task_manager.update_task_status.assert_not_called()
+
+    def test_03_shutdown_requeues_unfinished(self):
+        """Test tasks still running at the shutdown deadline are cancelled and put back to pending"""
+        worker = self._worker()
+        worker.task_manager.get_pending_tasks.return_value = [{'task_id': 'fast'}, {'task_id': 'slow'}]
+
+        async def process_task(task_info):
+            worker.stop()
+            await asyncio.sleep(0.01 if task_info['task_id'] == 'fast' else 60)
+
+        worker.process_task = process_task
+
+        async def run_and_drain():
+            await worker.run(check_interval=0)
+            return await worker.shutdown(timeout=0.1)
+
+        self.assertEqual(asyncio.run(run_and_drain()), 1)
+        worker.task_manager.update_task_status.assert_called_once_with(
+            'slow', status='pending', progress=0.0, message='Requeued after worker shutdown')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,118 @@
+#!/usr/bin/env python3
+"""
+Directory loader test file
+Test walking a local directory and bulk indexing it with a fake uploader and service
+"""
+import unittest
+import asyncio
+import os
+import sys
+import tempfile
+from unittest.mock import AsyncMock, MagicMock
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.config import init_config
+
+init_config(os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), 'config.template.yaml'))
+
+from utils.directory_loader import DirectoryItem, load_directory
+from tools.bulk_index import bulk_index
+
+
+def _write(path: str, content: str = '') -> str:
+    os.makedirs(os.path.dirname(path), exist_ok=True)
+    with open(path, 'w', encoding='utf-8') as f:
+        f.write(content)
+    return path
+
+
+class TestDirectoryLoader(unittest.TestCase):
+    """Directory loader test class"""
+
+    def setUp(self):
+        self._tmp = tempfile.TemporaryDirectory()
+        self.root = self._tmp.name
+        _write(os.path.join(self.root, 'a.jpg'))
+        _write(os.path.join(self.root, 'a.txt'), '  a red car \n')
+        _write(os.path.join(self.root, 'b.png'))
+        _write(os.path.join(self.root, 'c.txt'), 'just text')
+        _write(os.path.join(self.root, 'd.mp4'))
+        _write(os.path.join(self.root, 'notes.pdf'))
+        _write(os.path.join(self.root, '.hidden.jpg'))
+        _write(os.path.join(self.root, 'sub', 'e.JPEG'))
+        _write(os.path.join(self.root, 'sub', 'e.txt'), 'nested caption')
+
+    def tearDown(self):
+        self._tmp.cleanup()
+
+    def test_01_pairs_captions_and_skips_unsupported(self):
+        """Test media gets its sidecar caption, captions alone become text and other files are skipped"""
+        with self.assertLogs('utils.directory_loader', level='WARNING') as logs:
+            items = load_directory(self.root)
+
+        self.assertEqual(items, [
+            DirectoryItem(text='a red car', image_path=os.path.join(self.root, 'a.jpg')),
+            DirectoryItem(image_path=os.path.join(self.root, 'b.png')),
+            DirectoryItem(text='just text'),
+            DirectoryItem(video_path=os.path.join(self.root, 'd.mp4')),
+            DirectoryItem(text='nested caption', image_path=os.path.join(self.root, 'sub', 'e.JPEG')),
+        ])
+        self.assertEqual(len(logs.output), 1)
+        self.assertIn('notes.pdf', logs.output[0])
+
+    def test_02_not_a_directory(self):
+        """Test a missing directory is rejected"""
+        with self.assertRaises(NotADirectoryError):
+            load_directory(os.path.join(self.root, 'missing'))
+
+    def test_03_bulk_index(self):
+        """Test media is uploaded and the items are inserted in batches"""
+        uploads = []
+
+        def upload(file_path, file_type):
+            uploads.append((os.path.basename(file_path), file_type))
+            return f'https://oss.example.com/{os.path.basename(file_path)}'
+
+        service = MagicMock()
+        service.batch_insert_data = AsyncMock(side_effect=lambda data_list: len(data_list))
+
+        with self.assertLogs('utils.directory_loader', level='WARNING'):
+            report = asyncio.run(bulk_index(self.root, service, upload=upload, batch_size=2))
+
+        self.assertEqual((report.total, report.inserted, report.failures), (5, 5, []))
+        self.assertEqual(uploads, [('a.jpg', 'image'), ('b.png', 'image'), ('d.mp4', 'video'), ('e.JPEG', 'image')])
+        self.assertEqual(service.batch_insert_data.await_count, 3)
+        first = service.batch_insert_data.await_args_list[0].args[0][0]
+        self.assertEqual((first.text, first.image_url, first.video_url),
+                         ('a red car', 'https://oss.example.com/a.jpg', None))
+
+    def test_04_bulk_index_failures(self):
+        """Test upload and insert failures are reported per item without stopping the run"""
+        def upload(file_path, file_type):
+            if file_path.endswith('b.png'):
+                raise RuntimeError('OSS unavailable')
+            return f'https://oss.example.com/{os.path.basename(file_path)}'
+
+        async def insert_data(text='', image_url='', video_url=''):
+            if video_url:
+                raise ValueError('Video embedding failed')
+            return []
+
+        service = MagicMock()
+        service.batch_insert_data = AsyncMock(side_effect=ValueError('Batch failed'))
+        service.insert_data = AsyncMock(side_effect=insert_data)
+
+        with self.assertLogs('utils.directory_loader', level='WARNING'):
+            report = asyncio.run(bulk_index(self.root, service, upload=upload, batch_size=10))
+
+        self.assertEqual((report.total, report.inserted), (5, 3))
+        self.assertEqual(report.failures, [
+            (os.path.join(self.root, 'b.png'), 'upload failed: OSS unavailable'),
+            (os.path.join(self.root, 'd.mp4'), 'Video embedding failed'),
+        ])
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -15,8 +15,8 @@ import pytest
 sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
 
 from search_engine.elasticsearch.es import ESSearchEngine
-from search_engine.base import SearchInput, SearchOutput, InsertData, EmbeddingInfo
-from test_data import TEST_DATA, SEARCH_TEST_CASES, EMBEDDING_LABEL_TEST_CASES
+from search_engine.base import SearchInput, SearchOutput, InsertData, EmbeddingInfo, DocumentNotFoundError
+from test_data import TEST_DATA, SEARCH_TEST_CASES, EMBEDDING_LABEL_TEST_CASES, UNMAPPED_EMBEDDING_LABELS
 
 
 class TestESSearchEngine(unittest.TestCase):
@@ -296,6 +296,10 @@ class TestESSearchEngine(unittest.TestCase):
                 actual_field = self.search_engine._get_embedding_field(input_label)
                 self.assertEqual(actual_field, expected_field,
                                f"Label '{input_label}' should map to '{expected_field}', but actually maps to '{actual_field}'")
+        for input_label in UNMAPPED_EMBEDDING_LABELS:
+            with self.subTest(input_label=input_label):
+                with self.assertRaises(ValueError):
+                    self.search_engine._get_embedding_field(input_label)
 
     async def test_11_empty_search(self):
         """Test empty search (no condition search)"""
@@ -388,7 +392,214 @@ class TestESSearchEngine(unittest.TestCase):
         self.assertIsInstance(item.video, str)
         self.assertIsInstance(item.score, (int, float))
 
-    async def _insert_test_data(self):
+    async def test_17_named_vector_fields(self):
+        """Test indexing into two configured named vector fields and querying each independently"""
+        dims = len(TEST_DATA[0]["text_embedding"])
+        engine = ESSearchEngine({
+            **self.es_param,
+            "index": f"{self.test_index}_named",
+            "vector_fields": {"title_vector": dims, "body_vector": dims}
+        })
+        try:
+            title_embedding = TEST_DATA[0]["text_embedding"]
+            body_embedding = TEST_DATA[1]["text_embedding"]
+            await engine.batch_insert([
+                InsertData(id="title", text="title document",
+                           embeddings=[EmbeddingInfo(label="title_vector", embedding=title_embedding)]),
+                InsertData(id="body", text="body document",
+                           embeddings=[EmbeddingInfo(label="body_vector", embedding=body_embedding)]),
+            ])
+            await asyncio.sleep(1)
+
+            mapping = await engine.es.indices.get_mapping(index=engine.index_name)
+            properties = mapping[engine.index_name]["mappings"]["properties"]
+            self.assertEqual(properties["title_vector"]["dims"], dims)
+            self.assertNotIn("text_embedding", properties)
+
+            results = await engine.search(SearchInput(
+                embeddings=[EmbeddingInfo(label="title_vector", embedding=title_embedding)], topk=1))
+            self.assertEqual(results.items[0].id, "title")
+
+            results = await engine.search(SearchInput(
+                embeddings=[EmbeddingInfo(label="body_vector", embedding=body_embedding)], topk=1))
+            self.assertEqual(results.items[0].id, "body")
+
+            with self.assertRaises(ValueError):
+                await engine.search(SearchInput(
+                    embeddings=[EmbeddingInfo(label="text_embedding", embedding=title_embedding)]))
+        finally:
+            await engine.es.options(ignore_status=[400, 404]).indices.delete(index=engine.index_name)
+            await engine.close()
+
+    async def test_18_search_similar(self):
+        """Test nearest stored neighbour of a document excludes the document itself"""
+        base = TEST_DATA[0]["text_embedding"]
+        near = [v + 0.01 for v in base]
+        far = [-v for v in base]
+
+        await self.search_engine.batch_insert([
+            InsertData(id="anchor", text="anchor", embeddings=[EmbeddingInfo(label="text_embedding", embedding=base)]),
+            InsertData(id="near", text="near", embeddings=[EmbeddingInfo(label="text_embedding", embedding=near)]),
+            InsertData(id="far", text="far", embeddings=[EmbeddingInfo(label="text_embedding", embedding=far)]),
+        ])
+        await asyncio.sleep(1)
+
+        results = await self.search_engine.search_similar("anchor", topk=2)
+        self.assertEqual([item.id for item in results.items], ["near", "far"])
+
+        with self.assertRaises(DocumentNotFoundError):
+            await self.search_engine.search_similar("missing")
+
+    async def test_19_scan(self):
+        """Test scan enumerates every document exactly once in batches"""
+        await self.search_engine.batch_insert([
+            InsertData(id=f"scan_{i}", text=f"scan document {i}") for i in range(250)
+        ])
+        await asyncio.sleep(1)
+
+        ids = []
+        async for batch in self.search_engine.scan(batch_size=100):
+            self.assertLessEqual(len(batch), 100)
+            ids.extend(item.id for item in batch)
+
+        self.assertEqual(len(ids), 250)
+        self.assertEqual(set(ids), {f"scan_{i}" for i in range(250)})
+
+    async def test_20_keyword_highlights(self):
+        """Test keyword hit returns a highlighted fragment containing the query term"""
+        await self._insert_test_data()
+
+        results = await self.search_engine.search(SearchInput(text="machine learning", topk=3))
+
+        self.assertGreater(len(results.items), 0)
+        fragments = " ".join(results.items[0].highlights).lower()
+        self.assertIn("<em>", fragments)
+        self.assertIn("learning", fragments)
+
+    async def test_21_exact_and_approximate_search(self):
+        """Test exact and approximate vector search return the same top result on a small index"""
+        await self._insert_test_data()
+
+        from test_data import BASE_TEXT_EMBEDDING, generate_similar_embedding
+        query = [EmbeddingInfo(label="text_embedding", embedding=generate_similar_embedding(BASE_TEXT_EMBEDDING, 0.95))]
+
+        exact = await self.search_engine.search(SearchInput(embeddings=query, topk=3, exact=True))
+        approximate = await self.search_engine.search(SearchInput(embeddings=query, topk=3, exact=False))
+
+        self.assertGreater(len(exact.items), 0)
+        self.assertGreater(len(approximate.items), 0)
+        self.assertEqual(exact.items[0].id, approximate.items[0].id)
+
+    async def test_22_boost_outranks_similarity(self):
+        """Test a boosted lower-similarity document outranks an unboosted higher-similarity one"""
+        base = TEST_DATA[0]["text_embedding"]
+        closer = [v + 0.01 for v in base]
+        farther = [v + (0.2 if i % 2 else -0.2) for i, v in enumerate(base)]
+
+        await self.search_engine.batch_insert([
+            InsertData(id="plain", text="plain", embeddings=[EmbeddingInfo(label="text_embedding", embedding=closer)]),
+            InsertData(id="boosted", text="boosted", boost=3.0, embeddings=[EmbeddingInfo(label="text_embedding", embedding=farther)]),
+        ])
+        await asyncio.sleep(1)
+
+        results = await self.search_engine.search(SearchInput(embeddings=[EmbeddingInfo(label="text_embedding", embedding=base)], topk=2))
+
+        self.assertEqual([item.id for item in results.items], ["boosted", "plain"])
+
+    async def test_23_get_by_id(self):
+        """Test get returns exactly what was inserted and raises for a missing id"""
+        test_data = TEST_DATA[0]
+        data = InsertData(
+            id="get_me",
+            text=test_data["text"],
+            image=test_data["image"],
+            video=test_data["video"],
+            embeddings=[EmbeddingInfo(label="text_embedding", embedding=test_data["text_embedding"])]
+        )
+        await self.search_engine.insert(data)
+
+        self.assertEqual(await self.search_engine.get("get_me"), data)
+
+        with self.assertRaises(DocumentNotFoundError):
+            await self.search_engine.get("missing")
+
+    async def test_24_stats(self):
+        """Test stats document count matches the number of inserted documents"""
+        await self._insert_test_data()
+
+        stats = await self.search_engine.stats()
+
+        self.assertEqual(stats.doc_count, len(TEST_DATA))
+        self.assertGreater(stats.size_bytes, 0)
+        self.assertEqual(stats.vector_dims["text_embedding"], self.search_engine.vector_dimensions.text_embedding)
+
+    async def test_25_metric_override(self):
+        """Test switching the metric changes the ranking where cosine and dot product disagree"""
+        dims = self.search_engine.vector_dimensions.text_embedding
+
+        def vector(*head):
+            return list(head) + [0.0] * (dims - len(head))
+
+        # "aligned" points exactly along the query, "long" is less aligned but has a larger magnitude
+        await self.search_engine.batch_insert([
+            InsertData(id="aligned", text="aligned", embeddings=[EmbeddingInfo(label="text_embedding", embedding=vector(0.5, 0.0))]),
+            InsertData(id="long", text="long", embeddings=[EmbeddingInfo(label="text_embedding", embedding=vector(1.6, 1.2))]),
+        ])
+        await asyncio.sleep(1)
+
+        query = [EmbeddingInfo(label="text_embedding", embedding=vector(1.0, 0.0))]
+        cosine = await self.search_engine.search(SearchInput(embeddings=query, topk=2, metric="cosine"))
+        dot = await self.search_engine.search(SearchInput(embeddings=query, topk=2, metric="dot_product"))
+
+        self.assertEqual(cosine.items[0].id, "aligned")
+        self.assertEqual(dot.items[0].id, "long")
+
+        with self.assertRaises(ValueError):
+            await self.search_engine.search(SearchInput(embeddings=query, exact=False, metric="dot_product"))
+
+    async def test_25b_metric_matching_index_on_ann_path(self):
+        """Test a metric equal to the index similarity is accepted on the approximate path"""
+        embedding = TEST_DATA[0]["text_embedding"]
+        await self.search_engine.insert(InsertData(
+            id="ann", text="ann", embeddings=[EmbeddingInfo(label="text_embedding", embedding=embedding)]))
+        await asyncio.sleep(1)
+
+        results = await self.search_engine.search(SearchInput(
+            embeddings=[EmbeddingInfo(label="text_embedding", embedding=embedding)], topk=1, exact=False, metric="cosine"))
+        self.assertEqual(results.items[0].id, "ann")
+
+    async def test_26_include_vectors(self):
+        """Test results carry the stored vector only when include_vectors is set"""
+        embedding = TEST_DATA[0]["text_embedding"]
+        await self.search_engine.insert(InsertData(
+            id="with_vector",
+            text=TEST_DATA[0]["text"],
+            embeddings=[EmbeddingInfo(label="text_embedding", embedding=embedding)]
+        ))
+        await asyncio.sleep(1)
+
+        plain = await self.search_engine.search(SearchInput(text=TEST_DATA[0]["text"], topk=1))
+        self.assertEqual(plain.items[0].embeddings, [])
+
+        with_vectors = await self.search_engine.search(SearchInput(text=TEST_DATA[0]["text"], topk=1, include_vectors=True))
+        self.assertEqual(with_vectors.items[0].embeddings, [EmbeddingInfo(label="text_embedding", embedding=embedding)])
+
+    async def test_27_search_similar_mixed_modalities(self):
+        """Test similar search skips documents that lack the anchor's vector fields instead of failing"""
+        text_embedding = TEST_DATA[0]["text_embedding"]
+        image_embedding = TEST_DATA[0]["image_embedding"]
+
+        await self.search_engine.batch_insert([
+            InsertData(id="anchor", text="anchor", embeddings=[EmbeddingInfo(label="text_embedding", embedding=text_embedding)]),
+            InsertData(id="text_only", text="text only", embeddings=[EmbeddingInfo(label="text_embedding", embedding=[v + 0.01 for v in text_embedding])]),
+            InsertData(id="image_only", image="https://example.com/a.jpg", embeddings=[EmbeddingInfo(label="image_embedding", embedding=image_embedding)]),
+        ])
+        await asyncio.sleep(1)
+
+        results = await self.search_engine.search_similar("anchor", topk=5)
+        self.assertEqual([item.id for item in results.items], ["text_only"])
+
+
         """Insert test data helper method"""
         batch_data = []
         
@@ -0,0 +1,85 @@
+#!/usr/bin/env python3
+"""
+Lifecycle test file
+Test that closing the service, API and worker releases the search engine exactly once
+"""
+import unittest
+import asyncio
+import os
+import sys
+from unittest.mock import AsyncMock, MagicMock
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.config import init_config
+
+init_config(os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), 'config.template.yaml'))
+
+from handlers import search_handler
+from handlers.search_service import SearchService
+from workers.async_worker import AsyncWorker
+
+
+def _service_with_mock_engine():
+    """Build an initialized SearchService backed by a mocked search engine"""
+    service = SearchService()
+    service.search_engine = MagicMock()
+    service.search_engine.close = AsyncMock()
+    service.mm_extractor = MagicMock()
+    service.initialized = True
+    return service
+
+
+class TestLifecycle(unittest.TestCase):
+    """Close lifecycle test class"""
+
+    def test_01_service_close_once(self):
+        """Test SearchService.close closes the engine once and is safe to call again"""
+        service = _service_with_mock_engine()
+        engine = service.search_engine
+
+        asyncio.run(service.close())
+        asyncio.run(service.close())
+
+        engine.close.assert_awaited_once()
+        self.assertIsNone(service.search_engine)
+        self.assertFalse(service.initialized)
+
+    def test_02_service_close_engine_error(self):
+        """Test SearchService.close releases the engine even when close raises"""
+        service = _service_with_mock_engine()
+        service.search_engine.close.side_effect = RuntimeError('connection reset')
+
+        asyncio.run(service.close())
+
+        self.assertIsNone(service.search_engine)
+
+    def test_03_close_search_service(self):
+        """Test close_search_service closes the shared service once and clears it"""
+        service = _service_with_mock_engine()
+        engine = service.search_engine
+        search_handler.search_service = service
+
+        asyncio.run(search_handler.close_search_service())
+        asyncio.run(search_handler.close_search_service())
+
+        engine.close.assert_awaited_once()
+        self.assertIsNone(search_handler.search_service)
+
+    def test_04_worker_close(self):
+        """Test AsyncWorker.close closes its search service once"""
+        worker = AsyncWorker()
+        service = _service_with_mock_engine()
+        engine = service.search_engine
+        worker.search_service = service
+
+        asyncio.run(worker.close())
+        asyncio.run(worker.close())
+
+        engine.close.assert_awaited_once()
+        self.assertIsNone(worker.search_service)
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,58 @@
+#!/usr/bin/env python3
+"""
+Logger test file
+Test log output format configuration
+"""
+import unittest
+import io
+import json
+import logging
+import os
+import sys
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.logger import configure_logging, get_logger
+
+
+class TestLogger(unittest.TestCase):
+    """Logger configuration test class"""
+
+    def tearDown(self):
+        configure_logging()
+
+    def test_01_json_format(self):
+        """Test JSON format emits one parseable object per line with expected fields"""
+        stream = io.StringIO()
+        configure_logging(level='INFO', log_format='json', stream=stream)
+
+        get_logger('tests.logger').info('indexed %d documents', 3)
+
+        entry = json.loads(stream.getvalue().strip())
+        self.assertEqual(entry['level'], 'INFO')
+        self.assertEqual(entry['logger'], 'tests.logger')
+        self.assertEqual(entry['message'], 'indexed 3 documents')
+        self.assertIn('timestamp', entry)
+
+    def test_02_text_format_default(self):
+        """Test text format stays the default"""
+        stream = io.StringIO()
+        configure_logging(stream=stream)
+
+        get_logger('tests.logger').warning('plain message')
+
+        self.assertIn(' - tests.logger - WARNING - plain message', stream.getvalue())
+
+    def test_03_level_filter(self):
+        """Test configured level filters lower records"""
+        stream = io.StringIO()
+        configure_logging(level='warning', log_format='json', stream=stream)
+
+        get_logger('tests.logger').info('hidden')
+
+        self.assertEqual(stream.getvalue(), '')
+
+
+if __name__ == '__main__':
+    unittest.main()
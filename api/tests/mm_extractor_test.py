@@ -344,6 +344,87 @@ class TestMMExtractor(unittest.TestCase):
             self.assertIn('param', plugins[plugin_name])
             self.assertIn('api_key', plugins[plugin_name]['param'])
 
+    def test_09a_vlm_failure_degradation(self):
+        """Test image embedding is kept when VLM captioning fails"""
+        with patch('processor.pipelines.mm_extractor.ASRPlugin') as mock_asr_class, \
+             patch('processor.pipelines.mm_extractor.TEmbedPlugin') as mock_tembed_class, \
+             patch('processor.pipelines.mm_extractor.IEmbedPlugin') as mock_iembed_class, \
+             patch('processor.pipelines.mm_extractor.VEmbedPlugin') as mock_vembed_class, \
+             patch('processor.pipelines.mm_extractor.VLMPlugin') as mock_vlm_class:
+
+            mock_iembed_instance = Mock()
+            mock_iembed_instance.forward = AsyncMock(return_value=DataIO(embeddings=[[0.4, 0.5, 0.6]]))
+            mock_iembed_class.return_value = mock_iembed_instance
+
+            mock_vlm_instance = Mock()
+            mock_vlm_instance.forward = AsyncMock(side_effect=Exception("VLM unavailable"))
+            mock_vlm_class.return_value = mock_vlm_instance
+
+            mock_tembed_instance = Mock()
+            mock_tembed_instance.forward = AsyncMock(return_value=DataIO(embeddings=[[0.7, 0.8, 0.9]]))
+            mock_tembed_class.return_value = mock_tembed_instance
+
+            mock_asr_class.return_value = Mock()
+            mock_vembed_class.return_value = Mock()
+
+            extractor = MMExtractor(self.pipeline_param)
+            with self.assertLogs('processor.pipelines.mm_extractor', level='WARNING'):
+                result = asyncio.run(extractor.forward(self.test_image_data))
+
+            # Image embedding survives, caption is skipped and recorded as warning
+            self.assertEqual(result.image.image_embedding, [0.4, 0.5, 0.6])
+            self.assertEqual(result.image.text, '')
+            self.assertEqual(result.image.text_embeddings, [])
+            self.assertEqual(len(result.warnings), 1)
+            self.assertIn("VLM unavailable", result.warnings[0])
+            mock_tembed_instance.forward.assert_not_called()
+
+    def test_09b_asr_failure_degradation(self):
+        """Test video embedding is kept when ASR fails"""
+        with patch('processor.pipelines.mm_extractor.ASRPlugin') as mock_asr_class, \
+             patch('processor.pipelines.mm_extractor.TEmbedPlugin') as mock_tembed_class, \
+             patch('processor.pipelines.mm_extractor.IEmbedPlugin') as mock_iembed_class, \
+             patch('processor.pipelines.mm_extractor.VEmbedPlugin') as mock_vembed_class, \
+             patch('processor.pipelines.mm_extractor.VLMPlugin') as mock_vlm_class:
+
+            mock_vembed_instance = Mock()
+            mock_vembed_instance.forward = AsyncMock(return_value=DataIO(embeddings=[[0.1, 0.2, 0.3]]))
+            mock_vembed_class.return_value = mock_vembed_instance
+
+            mock_asr_instance = Mock()
+            mock_asr_instance.forward = AsyncMock(side_effect=Exception("ASR unavailable"))
+            mock_asr_class.return_value = mock_asr_instance
+
+            mock_tembed_instance = Mock()
+            mock_tembed_instance.forward = AsyncMock(return_value=DataIO(embeddings=[[0.7, 0.8, 0.9]]))
+            mock_tembed_class.return_value = mock_tembed_instance
+
+            mock_iembed_class.return_value = Mock()
+            mock_vlm_class.return_value = Mock()
+
+            extractor = MMExtractor(self.pipeline_param)
+            with self.assertLogs('processor.pipelines.mm_extractor', level='WARNING'):
+                result = asyncio.run(extractor.forward(self.test_video_data))
+
+            # Video embedding survives, transcript is skipped and recorded as warning
+            self.assertEqual(result.video.video_embedding, [0.1, 0.2, 0.3])
+            self.assertEqual(result.video.text, '')
+            self.assertEqual(result.video.text_embeddings, [])
+            self.assertEqual(len(result.warnings), 1)
+            self.assertIn("ASR unavailable", result.warnings[0])
+            mock_tembed_instance.forward.assert_not_called()
+
+    def test_09c_aliyun_asr_raises(self):
+        """Test the Aliyun ASR plugin surfaces failures instead of returning empty text"""
+        from processor.plugins.asr.aliyun import AliyunASR, AliyunASRParam
+
+        asr = AliyunASR(AliyunASRParam(model='paraformer-realtime-v2'))
+        with patch('processor.plugins.asr.aliyun.AudioExtractor') as mock_extractor_class:
+            mock_extractor_class.return_value.extract_audio.side_effect = Exception("Video URL download error")
+            with self.assertRaises(Exception) as ctx:
+                asyncio.run(asr.forward(DataIO(video="https://example.com/test.mp4")))
+        self.assertIn("Video URL download error", str(ctx.exception))
+
 
 class TestMMExtractorRealAPI(unittest.TestCase):
     """MMExtractor real API test class"""
@@ -0,0 +1,314 @@
+#!/usr/bin/env python3
+"""
+OpenSearchEngine test file
+Test index mapping and query building with a mocked OpenSearch client
+"""
+import unittest
+import asyncio
+import os
+import sys
+from unittest.mock import AsyncMock, Mock, patch
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from search_engine.opensearch.opensearch import OpenSearchEngine, SpaceType, NotFoundError
+from search_engine.base import SearchInput, InsertData, EmbeddingInfo, SearchEngineFactory, SearchEngineParam, SearchEngineType, DocumentNotFoundError
+from tests.test_data import EMBEDDING_LABEL_TEST_CASES, UNMAPPED_EMBEDDING_LABELS
+
+
+def _mock_client(exists: bool = False, hits=None) -> Mock:
+    client = Mock()
+    client.indices.exists = AsyncMock(return_value=exists)
+    client.indices.create = AsyncMock()
+    client.search = AsyncMock(return_value={'hits': {'total': {'value': len(hits or [])}, 'hits': hits or []}})
+    return client
+
+
+class TestOpenSearchEngine(unittest.TestCase):
+    """OpenSearchEngine test class"""
+
+    def setUp(self):
+        self.param = {
+            'host': 'localhost',
+            'port': 9200,
+            'index': 'test_index',
+            'space_type': SpaceType.INNER_PRODUCT,
+            'vector_dimensions': {'text_embedding': 4, 'image_embedding': 8, 'video_embedding': 8},
+        }
+
+    def test_01_factory_creates_opensearch(self):
+        """Test factory resolves the opensearch type"""
+        engine = SearchEngineFactory(SearchEngineParam(type=SearchEngineType.OPENSEARCH, param=self.param)).get_search_engine()
+        self.assertIsInstance(engine, OpenSearchEngine)
+
+    def test_02_invalid_space_type(self):
+        """Test unsupported space_type is rejected"""
+        with self.assertRaises(ValueError):
+            OpenSearchEngine({**self.param, 'space_type': 'hamming'})
+
+    def test_03_index_mapping(self):
+        """Test index is created with k-NN settings and knn_vector fields"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=False)
+
+        asyncio.run(engine._ensure_index())
+
+        body = engine.client.indices.create.call_args.kwargs['body']
+        self.assertTrue(body['settings']['index']['knn'])
+        text_field = body['mappings']['properties']['text_embedding']
+        self.assertEqual(text_field['type'], 'knn_vector')
+        self.assertEqual(text_field['dimension'], 4)
+        self.assertEqual(text_field['method']['space_type'], SpaceType.INNER_PRODUCT)
+        self.assertEqual(body['mappings']['properties']['image_embedding']['dimension'], 8)
+
+    def test_04_knn_query(self):
+        """Test hybrid search builds multi_match and knn clauses"""
+        hits = [{'_score': 0.9, '_source': {'text': 'doc', 'image': '', 'video': ''}}]
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True, hits=hits)
+
+        output = asyncio.run(engine.search(SearchInput(
+            text='query',
+            embeddings=[EmbeddingInfo(label='image_embedding', embedding=[0.1] * 8)],
+            topk=5,
+            exact=False,
+        )))
+
+        body = engine.client.search.call_args.kwargs['body']
+        should = body['query']['function_score']['query']['bool']['should']
+        self.assertIn('multi_match', should[0])
+        self.assertEqual(should[1]['knn']['image_embedding']['k'], 5)
+        self.assertEqual(body['size'], 5)
+        self.assertEqual(len(output.items), 1)
+        self.assertEqual(output.items[0].text, 'doc')
+        self.assertEqual(output.items[0].score, 0.9)
+
+    def test_05_search_similar(self):
+        """Test similar search scores stored vectors exactly like default search and excludes the document itself"""
+        hits = [{'_id': 'near', '_score': 0.8, '_source': {'text': 'near'}}]
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True, hits=hits)
+        engine.client.get = AsyncMock(return_value={'_id': 'anchor', '_source': {'text_embedding': [0.1] * 4}})
+
+        output = asyncio.run(engine.search_similar('anchor', topk=3))
+
+        query = engine.client.search.call_args.kwargs['body']['query']['function_score']['query']['bool']
+        self.assertEqual(query['must_not'], [{'ids': {'values': ['anchor']}}])
+        script_score = query['should'][0]['script_score']
+        self.assertEqual(script_score['query'], {'exists': {'field': 'text_embedding'}})
+        self.assertEqual(script_score['script']['source'], 'knn_score')
+        self.assertEqual(script_score['script']['params']['space_type'], SpaceType.INNER_PRODUCT)
+        self.assertEqual(output.items[0].id, 'near')
+
+    def test_06_search_similar_not_found(self):
+        """Test similar search on a missing id raises DocumentNotFoundError"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        engine.client.get = AsyncMock(side_effect=NotFoundError())
+
+        with self.assertRaises(DocumentNotFoundError):
+            asyncio.run(engine.search_similar('missing'))
+
+    def test_07_scan_batches(self):
+        """Test scan enumerates every document exactly once in bounded batches"""
+        hits = [{'_id': f'doc_{i}', '_score': None, '_source': {'text': f'text {i}'}} for i in range(250)]
+
+        async def _fake_scan(client, **kwargs):
+            for hit in hits:
+                yield hit
+
+        async def _collect(engine):
+            return [batch async for batch in engine.scan(batch_size=100)]
+
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        with patch('opensearchpy.helpers.async_scan', new=_fake_scan):
+            batches = asyncio.run(_collect(engine))
+
+        self.assertEqual([len(batch) for batch in batches], [100, 100, 50])
+        ids = [item.id for batch in batches for item in batch]
+        self.assertEqual(len(set(ids)), 250)
+
+    def test_08_keyword_highlights(self):
+        """Test keyword search requests highlighting and returns fragments, vector search does not"""
+        hits = [{'_id': 'a', '_score': 1.2, '_source': {'text': 'deep learning basics'},
+                 'highlight': {'text': ['deep <em>learning</em> basics']}}]
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True, hits=hits)
+
+        output = asyncio.run(engine.search(SearchInput(text='learning', topk=5)))
+
+        body = engine.client.search.call_args.kwargs['body']
+        self.assertIn('text', body['highlight']['fields'])
+        self.assertEqual(output.items[0].highlights, ['deep <em>learning</em> basics'])
+
+        asyncio.run(engine.search(SearchInput(embeddings=[EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)])))
+        self.assertNotIn('highlight', engine.client.search.call_args.kwargs['body'])
+
+    def test_09_exact_search(self):
+        """Test default mode uses brute-force knn_score like ES and approximate mode uses the ANN index"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        embeddings = [EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)]
+
+        asyncio.run(engine.search(SearchInput(embeddings=embeddings)))
+        script_score = engine.client.search.call_args.kwargs['body']['query']['function_score']['query']['script_score']
+        # Documents of other modalities lack the field and are not scored
+        self.assertEqual(script_score['query'], {'exists': {'field': 'text_embedding'}})
+        script = script_score['script']
+        self.assertEqual(script['source'], 'knn_score')
+        self.assertEqual(script['params']['field'], 'text_embedding')
+        self.assertEqual(script['params']['space_type'], SpaceType.INNER_PRODUCT)
+
+        asyncio.run(engine.search(SearchInput(embeddings=embeddings, exact=False)))
+        self.assertIn('knn', engine.client.search.call_args.kwargs['body']['query']['function_score']['query'])
+
+    def test_10_boost(self):
+        """Test stored boost is indexed and multiplied into the relevance score"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+
+        doc = engine._build_doc(InsertData(text='promoted', boost=2.5))
+        self.assertEqual(doc['boost'], 2.5)
+
+        asyncio.run(engine.search(SearchInput(text='promoted')))
+        function_score = engine.client.search.call_args.kwargs['body']['query']['function_score']
+        self.assertEqual(function_score['field_value_factor'], {'field': 'boost', 'missing': 1.0})
+        self.assertEqual(function_score['boost_mode'], 'multiply')
+
+    def test_11_get_round_trip(self):
+        """Test get returns what was inserted and raises for a missing id"""
+        data = InsertData(id='doc_1', text='hello', image='https://example.com/a.jpg', boost=2.0,
+                          embeddings=[EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)])
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        engine.client.get = AsyncMock(return_value={'_id': 'doc_1', '_source': engine._build_doc(data)})
+
+        self.assertEqual(asyncio.run(engine.get('doc_1')), data)
+
+        engine.client.get = AsyncMock(side_effect=NotFoundError())
+        with self.assertRaises(DocumentNotFoundError):
+            asyncio.run(engine.get('missing'))
+
+    def test_12_fields_projection(self):
+        """Test requesting only id disables _source and returns results without payload"""
+        hits = [{'_id': 'a', '_score': 0.9}]
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True, hits=hits)
+
+        output = asyncio.run(engine.search(SearchInput(text='query', fields=['id'])))
+
+        self.assertFalse(engine.client.search.call_args.kwargs['body']['_source'])
+        self.assertEqual(output.items[0].id, 'a')
+        self.assertEqual(output.items[0].score, 0.9)
+        self.assertEqual(output.items[0].text, '')
+
+        asyncio.run(engine.search(SearchInput(text='query', fields=['id', 'text'])))
+        self.assertEqual(engine.client.search.call_args.kwargs['body']['_source'], ['text'])
+
+    def test_13_stats(self):
+        """Test stats reports document count, size and vector dimensions"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        engine.client.count = AsyncMock(return_value={'count': 42})
+        engine.client.indices.stats = AsyncMock(return_value={
+            '_all': {'primaries': {'docs': {'count': 42, 'deleted': 3}, 'store': {'size_in_bytes': 2048}}}
+        })
+
+        stats = asyncio.run(engine.stats())
+
+        self.assertEqual(stats.doc_count, 42)
+        self.assertEqual(stats.deleted_count, 3)
+        self.assertEqual(stats.size_bytes, 2048)
+        self.assertEqual(stats.vector_dims['text_embedding'], 4)
+
+    def test_14_metric_override(self):
+        """Test metric override applies to exact search and is rejected for approximate search"""
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True)
+        embeddings = [EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)]
+
+        asyncio.run(engine.search(SearchInput(embeddings=embeddings, metric='l2')))
+        script = engine.client.search.call_args.kwargs['body']['query']['function_score']['query']['script_score']['script']
+        self.assertEqual(script['params']['space_type'], SpaceType.L2)
+
+        with self.assertRaises(ValueError):
+            asyncio.run(engine.search(SearchInput(embeddings=embeddings, exact=False, metric='l2')))
+        # Same metric as the index is allowed on the ANN path
+        asyncio.run(engine.search(SearchInput(embeddings=embeddings, exact=False, metric='dot_product')))
+
+    def test_14b_cosine_metric_on_ann_path(self):
+        """Test metric=cosine on a cosine index uses the ANN path, matching ES"""
+        engine = OpenSearchEngine(dict(self.param, space_type=SpaceType.COSINE))
+        engine.client = _mock_client(exists=True)
+        embeddings = [EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)]
+
+        asyncio.run(engine.search(SearchInput(embeddings=embeddings, exact=False, metric='cosine')))
+        query = engine.client.search.call_args.kwargs['body']['query']['function_score']['query']
+        self.assertEqual(query['knn']['text_embedding']['k'], 10)
+
+    def test_15_include_vectors(self):
+        """Test vectors are excluded by default and returned when requested"""
+        hits = [{'_id': 'a', '_score': 0.9, '_source': {'text': 'a', 'text_embedding': [0.1] * 4}}]
+        engine = OpenSearchEngine(self.param)
+        engine.client = _mock_client(exists=True, hits=hits)
+
+        asyncio.run(engine.search(SearchInput(text='a')))
+        self.assertIn('text_embedding', engine.client.search.call_args.kwargs['body']['_source']['excludes'])
+
+        output = asyncio.run(engine.search(SearchInput(text='a', include_vectors=True)))
+        self.assertTrue(engine.client.search.call_args.kwargs['body']['_source'])
+        self.assertEqual(output.items[0].embeddings, [EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)])
+
+    def test_16_named_vector_fields(self):
+        """Test configured vector_fields drive the mapping and unknown labels are rejected"""
+        engine = OpenSearchEngine({**self.param, 'vector_fields': {'title_vector': 4, 'body_vector': 6}})
+        engine.client = _mock_client(exists=False)
+
+        asyncio.run(engine._ensure_index())
+
+        properties = engine.client.indices.create.call_args.kwargs['body']['mappings']['properties']
+        self.assertEqual(properties['title_vector']['dimension'], 4)
+        self.assertEqual(properties['body_vector']['dimension'], 6)
+        self.assertNotIn('text_embedding', properties)
+
+        asyncio.run(engine.search(SearchInput(embeddings=[EmbeddingInfo(label='body_vector', embedding=[0.1] * 6)], exact=False)))
+        should = engine.client.search.call_args.kwargs['body']['query']['function_score']['query']
+        self.assertIn('body_vector', should['knn'])
+
+        with self.assertRaises(ValueError):
+            asyncio.run(engine.search(SearchInput(embeddings=[EmbeddingInfo(label='text_embedding', embedding=[0.1] * 4)])))
+        with self.assertRaises(ValueError):
+            engine._build_doc(InsertData(embeddings=[EmbeddingInfo(label='unknown_label', embedding=[0.1] * 4)]))
+
+    def test_17_slow_search_warning(self):
+        """Test slow_threshold_ms is honoured for OpenSearch searches"""
+        engine = OpenSearchEngine({**self.param, 'slow_threshold_ms': 10})
+        engine.client = _mock_client(exists=True)
+        response = {'hits': {'total': {'value': 0}, 'hits': []}}
+
+        async def slow_search(**kwargs):
+            await asyncio.sleep(0.05)
+            return response
+
+        engine.client.search = AsyncMock(side_effect=slow_search)
+        with self.assertLogs('processor.utils.slow_call', level='WARNING') as logs:
+            asyncio.run(engine.search(SearchInput(text='query')))
+        self.assertIn('Slow OpenSearch search call', logs.output[0])
+
+    def test_18_embedding_label_mapping(self):
+        """Test labels route to their vector field and unmapped labels are rejected, not stored as text_embedding"""
+        engine = OpenSearchEngine(self.param)
+
+        for input_label, expected_field in EMBEDDING_LABEL_TEST_CASES:
+            with self.subTest(input_label=input_label):
+                self.assertEqual(engine._get_embedding_field(input_label), expected_field)
+        for input_label in UNMAPPED_EMBEDDING_LABELS:
+            with self.subTest(input_label=input_label):
+                with self.assertRaises(ValueError):
+                    engine._get_embedding_field(input_label)
+
+
+if __name__ == '__main__':
+    unittest.main()
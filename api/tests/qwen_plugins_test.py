@@ -0,0 +1,215 @@
+#!/usr/bin/env python3
+"""
+Qwen plugins test file
+Test embedding plugin behaviour with mocked DashScope calls
+"""
+import unittest
+import asyncio
+import os
+import sys
+from unittest.mock import AsyncMock, Mock, patch
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from processor.core import DataIO, PluginNotConfiguredError, UnsupportedPluginImplError
+from processor.plugins import TEmbedPluginParam, IEmbedPluginParam, VEmbedPluginParam, VLMPluginParam, ASRPluginParam
+from processor.plugins.tembed.qwen import QwenTEmbed, QwenTEmbedParam
+from processor.plugins.iembed.qwen import QwenIEmbed, QwenIEmbedParam
+from processor.utils.async_dashscope import DashScopeAPIError
+from processor.utils.dimension_check import DimensionMismatchError
+
+
+def _slow_text_embedding(delay: float):
+    async def _call(**kwargs):
+        await asyncio.sleep(delay)
+        return {'embeddings': [{'embedding': [0.1, 0.2, 0.3]}]}
+    return _call
+
+
+class TestQwenTEmbed(unittest.TestCase):
+    """QwenTEmbed test class"""
+
+    def test_01_slow_call_warning(self):
+        """Test slow call is reported and fast call is silent"""
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', slow_threshold_ms=50))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_slow_text_embedding(0.1))):
+            with self.assertLogs('processor.utils.slow_call', level='WARNING') as logs:
+                asyncio.run(plugin.forward(DataIO(text='slow query')))
+            self.assertIn('Slow text embedding call', logs.output[0])
+            self.assertIn('model=text-embedding-v4', logs.output[0])
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_slow_text_embedding(0))):
+            with self.assertNoLogs('processor.utils.slow_call', level='WARNING'):
+                asyncio.run(plugin.forward(DataIO(text='fast query')))
+
+    def test_02_expected_dim_mismatch(self):
+        """Test embedding of unexpected dimension raises DimensionMismatchError"""
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', expected_dim=1024))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_slow_text_embedding(0))):
+            with self.assertRaises(DimensionMismatchError) as ctx:
+                asyncio.run(plugin.forward(DataIO(text='query')))
+
+        self.assertEqual(ctx.exception.expected, 1024)
+        self.assertEqual(ctx.exception.got, 3)
+
+    def test_03_expected_dim_match(self):
+        """Test embedding of expected dimension passes through"""
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', expected_dim=3))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_slow_text_embedding(0))):
+            output = asyncio.run(plugin.forward(DataIO(text='query')))
+
+        self.assertEqual(output.embeddings, [[0.1, 0.2, 0.3]])
+
+
+    def test_04_max_concurrent(self):
+        """Test in-flight calls never exceed max_concurrent"""
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', max_concurrent=3))
+        in_flight = 0
+        peak = 0
+
+        async def _tracked_call(**kwargs):
+            nonlocal in_flight, peak
+            in_flight += 1
+            peak = max(peak, in_flight)
+            await asyncio.sleep(0.01)
+            in_flight -= 1
+            return {'embeddings': [{'embedding': [0.1, 0.2, 0.3]}]}
+
+        async def _burst():
+            await asyncio.gather(*(plugin.forward(DataIO(text=f'query {i}')) for i in range(20)))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_tracked_call)):
+            asyncio.run(_burst())
+
+        self.assertEqual(peak, 3)
+
+    def test_05_max_concurrent_across_event_loops(self):
+        """Test a limiter built outside any loop works when the plugin is driven by successive loops"""
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', max_concurrent=2))
+
+        async def _burst():
+            await asyncio.gather(*(plugin.forward(DataIO(text=f'query {i}')) for i in range(5)))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_slow_text_embedding(0.01))):
+            asyncio.run(_burst())
+            asyncio.run(_burst())
+
+    def test_06_adaptive_concurrency_backs_off_on_rate_limit(self):
+        """Test adaptive concurrency ramps up from min and settles under a server that 429s above a threshold"""
+        threshold = 6
+        plugin = QwenTEmbed(QwenTEmbedParam(api_key='test_key', max_concurrent=20,
+                                            adaptive_concurrency=True, min_concurrent=1))
+        self.assertEqual(plugin.stats()['concurrency'], 1)
+        in_flight = 0
+        peak = 0
+        limits = []
+
+        async def _rate_limited_server(**kwargs):
+            nonlocal in_flight, peak
+            in_flight += 1
+            peak = max(peak, in_flight)
+            try:
+                await asyncio.sleep(0.001)
+                if in_flight > threshold:
+                    raise DashScopeAPIError('Text embedding', 429, code='Throttling.RateQuota')
+                return {'embeddings': [{'embedding': [0.1, 0.2, 0.3]}]}
+            finally:
+                in_flight -= 1
+
+        async def _client(calls: int):
+            for i in range(calls):
+                while True:
+                    try:
+                        await plugin.forward(DataIO(text=f'query {i}'))
+                        break
+                    except DashScopeAPIError:
+                        pass
+                    finally:
+                        limits.append(plugin.stats()['concurrency'])
+
+        async def _load():
+            await asyncio.gather(*(_client(20) for _ in range(20)))
+
+        with patch('processor.plugins.tembed.qwen.AsyncDashScope.text_embedding',
+                   new=AsyncMock(side_effect=_rate_limited_server)):
+            asyncio.run(asyncio.wait_for(_load(), timeout=30))
+
+        stats = plugin.stats()
+        # Ramped up from 1 but never ran away towards max_concurrent
+        self.assertGreater(max(limits), 1)
+        self.assertLess(peak, 2 * threshold)
+        settled = limits[len(limits) // 2:]
+        self.assertLessEqual(sum(settled) / len(settled), threshold)
+        self.assertGreater(stats['rate_limited'], 0)
+        self.assertLess(stats['rate_limited'], len(limits) // 10)
+        self.assertEqual(stats['in_flight'], 0)
+
+    def test_07_adaptive_concurrency_needs_max(self):
+        """Test adaptive concurrency without a positive max_concurrent is rejected"""
+        with self.assertRaises(ValueError):
+            QwenTEmbed(QwenTEmbedParam(api_key='test_key', adaptive_concurrency=True))
+
+
+class TestQwenIEmbed(unittest.TestCase):
+    """QwenIEmbed test class"""
+
+    def test_01_parameters_passthrough(self):
+        """Test configured parameters are sent with the DashScope request"""
+        plugin = QwenIEmbed(QwenIEmbedParam(api_key='test_key', parameters={'auto_truncation': True}))
+        rsp = Mock(status_code=200, output={'embeddings': [{'embedding': [0.1, 0.2]}]})
+
+        with patch('processor.utils.async_dashscope.dashscope.MultiModalEmbedding.call', return_value=rsp) as call:
+            asyncio.run(plugin.forward(DataIO(image='https://example.com/a.jpg')))
+
+        self.assertTrue(call.call_args.kwargs['auto_truncation'])
+        self.assertEqual(call.call_args.kwargs['dimension'], 1024)
+
+    def test_02_nested_parameters_rejected(self):
+        """Test non-flat parameters are rejected at construction"""
+        with self.assertRaises(ValueError):
+            QwenIEmbed(QwenIEmbedParam(api_key='test_key', parameters={'crop': {'x': 1}}))
+
+    def test_03_reserved_parameters_rejected(self):
+        """Test parameters cannot override arguments the plugin already sends"""
+        for key in ['model', 'input', 'api_key', 'dimension']:
+            with self.subTest(key=key):
+                with self.assertRaises(ValueError):
+                    QwenIEmbed(QwenIEmbedParam(api_key='test_key', parameters={key: 'x'}))
+
+
+class TestPluginConfig(unittest.TestCase):
+    """Plugin param factory test class"""
+
+    def test_01_empty_impl_not_configured(self):
+        """Test empty impl reports not configured"""
+        with self.assertRaises(PluginNotConfiguredError):
+            TEmbedPluginParam.from_dict({'impl': '', 'param': {}})
+
+    def test_02_unknown_impl_unsupported(self):
+        """Test unknown impl reports unsupported, distinct from not configured"""
+        with self.assertRaises(UnsupportedPluginImplError) as ctx:
+            TEmbedPluginParam.from_dict({'impl': 'openai', 'param': {}})
+        self.assertNotIsInstance(ctx.exception, PluginNotConfiguredError)
+        self.assertEqual(ctx.exception.impl, 'openai')
+
+    def test_03_unknown_impl_without_param_unsupported(self):
+        """Test unknown impl is reported as unsupported even without a param block"""
+        for param_cls in [TEmbedPluginParam, IEmbedPluginParam, VEmbedPluginParam, VLMPluginParam, ASRPluginParam]:
+            with self.subTest(plugin=param_cls.__name__):
+                with self.assertRaises(UnsupportedPluginImplError) as ctx:
+                    param_cls.from_dict({'impl': 'openai'})
+                self.assertEqual(ctx.exception.impl, 'openai')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,144 @@
+#!/usr/bin/env python3
+"""
+Re-embed test file
+Test migrating a small in-memory index between two fake embedding providers
+"""
+import unittest
+import asyncio
+import os
+import sys
+from typing import Dict, List
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.config import init_config
+
+init_config(os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), 'config.template.yaml'))
+
+from processor.core.data import MMData, TextItem, ImageItem, VideoItem
+from search_engine.base import BaseSearchEngine, InsertData, SearchOutputItem, DocumentNotFoundError, derive_doc_id
+from handlers.search_service import build_insert_data
+from tools.reembed import reembed
+
+
+class InMemorySearchEngine(BaseSearchEngine):
+    """Dict backed engine with the scan, get and insert contract of the Lucene engines"""
+
+    def __init__(self):
+        super().__init__({})
+        self.docs: Dict[str, InsertData] = {}
+
+    async def insert(self, data: InsertData) -> None:
+        data.id = derive_doc_id(data)
+        self.docs[data.id] = data
+
+    async def get(self, doc_id: str) -> InsertData:
+        if doc_id not in self.docs:
+            raise DocumentNotFoundError(doc_id)
+        return self.docs[doc_id]
+
+    async def scan(self, batch_size: int = 500):
+        ids = sorted(self.docs)
+        for i in range(0, len(ids), batch_size):
+            yield [SearchOutputItem(id=doc_id, text=self.docs[doc_id].text) for doc_id in ids[i:i + batch_size]]
+
+
+class FakeProvider:
+    """Extractor returning constant vectors tagged with the provider version"""
+
+    def __init__(self, version: float, dead_urls: List[str] = ()):
+        self.version = version
+        self.dead_urls = dead_urls
+        self.in_flight = 0
+        self.peak = 0
+
+    async def forward(self, input: MMData) -> MMData:
+        self.in_flight += 1
+        self.peak = max(self.peak, self.in_flight)
+        try:
+            await asyncio.sleep(0.01)
+            if input.image and input.image.image in self.dead_urls:
+                raise ValueError('QwenIEmbedPlugin forward failed: image cannot be opened')
+            if input.text:
+                input.text.text_embeddings = [[self.version, 0.0]]
+            if input.image:
+                input.image.image_embedding = [self.version, 1.0]
+            if input.video:
+                input.video.video_embedding = [self.version, 2.0]
+            return input
+        finally:
+            self.in_flight -= 1
+
+
+class TestReembed(unittest.TestCase):
+    """Re-embed test class"""
+
+    def _index(self, provider: FakeProvider) -> InMemorySearchEngine:
+        engine = InMemorySearchEngine()
+
+        async def load():
+            for text, image, video in [('a red car', '', ''),
+                                       ('a cat', 'https://example.com/cat.jpg', ''),
+                                       ('', 'https://example.com/gone.jpg', ''),
+                                       ('', '', 'https://example.com/clip.mp4')]:
+                data = MMData(
+                    text=TextItem(text=text) if text else None,
+                    image=ImageItem(image=image) if image else None,
+                    video=VideoItem(video=video) if video else None
+                )
+                await engine.insert(build_insert_data(await provider.forward(data), text, image, video))
+
+        asyncio.run(load())
+        return engine
+
+    def test_01_migrate_between_providers(self):
+        """Test every document is re-embedded by the new provider under the same id"""
+        engine = self._index(FakeProvider(1.0))
+        engine.docs[sorted(engine.docs)[0]].boost = 2.5
+        ids = sorted(engine.docs)
+
+        provider = FakeProvider(2.0)
+        report = asyncio.run(reembed(engine, provider, concurrency=2, batch_size=3))
+
+        self.assertEqual((report.total, report.updated, report.failures), (4, 4, []))
+        self.assertEqual(sorted(engine.docs), ids)
+        for doc in engine.docs.values():
+            self.assertTrue(doc.embeddings)
+            self.assertTrue(all(info.embedding[0] == 2.0 for info in doc.embeddings))
+        self.assertEqual(engine.docs[ids[0]].boost, 2.5)
+        self.assertLessEqual(provider.peak, 2)
+
+    def test_02_dead_media_recorded(self):
+        """Test a dead media URL is reported and the document keeps its previous vectors"""
+        engine = self._index(FakeProvider(1.0))
+        gone_id = next(doc_id for doc_id, doc in engine.docs.items() if doc.image.endswith('gone.jpg'))
+
+        report = asyncio.run(reembed(engine, FakeProvider(2.0, dead_urls=['https://example.com/gone.jpg'])))
+
+        self.assertEqual((report.total, report.updated), (4, 3))
+        self.assertEqual(len(report.failures), 1)
+        self.assertEqual(report.failures[0][0], gone_id)
+        self.assertIn('dead media URL https://example.com/gone.jpg', report.failures[0][1])
+        self.assertEqual(engine.docs[gone_id].embeddings[0].embedding, [1.0, 1.0])
+
+    def test_03_missing_vector_not_overwritten(self):
+        """Test a document is left untouched when the new provider drops one of its vectors"""
+        engine = self._index(FakeProvider(1.0))
+        video_id = next(doc_id for doc_id, doc in engine.docs.items() if doc.video)
+
+        class NoVideoProvider(FakeProvider):
+            async def forward(self, input: MMData) -> MMData:
+                input.video = None
+                return await super().forward(input)
+
+        report = asyncio.run(reembed(engine, NoVideoProvider(2.0)))
+
+        self.assertEqual(report.updated, 3)
+        self.assertEqual([doc_id for doc_id, _ in report.failures], [video_id])
+        self.assertIn('video_embedding', report.failures[0][1])
+        self.assertEqual(engine.docs[video_id].embeddings[0].embedding, [1.0, 2.0])
+
+
+if __name__ == '__main__':
+    unittest.main()
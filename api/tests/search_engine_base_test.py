@@ -0,0 +1,116 @@
+#!/usr/bin/env python3
+"""
+Search engine base test file
+Test shared helpers used by all search engine implementations
+"""
+import unittest
+import os
+import sys
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from search_engine.base import (
+    InsertData, derive_doc_id, SearchEngineFactory, SearchEngineParam,
+    SearchEngineNotConfiguredError, UnsupportedSearchEngineError,
+    SearchInput, SearchOutputItem, ScoreNormalization, normalize_scores,
+)
+
+
+class TestDeriveDocID(unittest.TestCase):
+    """derive_doc_id test class"""
+
+    def test_01_identical_content_same_id(self):
+        """Test identical content yields identical ids"""
+        a = InsertData(text="hello", image="https://example.com/a.jpg")
+        b = InsertData(text="hello", image="https://example.com/a.jpg")
+        self.assertEqual(derive_doc_id(a), derive_doc_id(b))
+
+    def test_02_different_content_different_id(self):
+        """Test different content yields different ids"""
+        base = derive_doc_id(InsertData(text="hello", image="https://example.com/a.jpg"))
+        self.assertNotEqual(base, derive_doc_id(InsertData(text="hello", image="https://example.com/b.jpg")))
+        self.assertNotEqual(base, derive_doc_id(InsertData(text="hello!", image="https://example.com/a.jpg")))
+        # Same string in a different field must not collide
+        self.assertNotEqual(derive_doc_id(InsertData(image="x")), derive_doc_id(InsertData(video="x")))
+
+    def test_03_explicit_id_wins(self):
+        """Test explicit id overrides derived id"""
+        self.assertEqual(derive_doc_id(InsertData(id="doc-1", text="hello")), "doc-1")
+
+
+
+class TestSearchEngineFactory(unittest.TestCase):
+    """SearchEngineFactory test class"""
+
+    def test_01_empty_type_not_configured(self):
+        """Test empty engine type reports not configured"""
+        with self.assertRaises(SearchEngineNotConfiguredError):
+            SearchEngineFactory(SearchEngineParam(type='')).get_search_engine()
+
+    def test_02_unknown_type_unsupported(self):
+        """Test unknown engine type reports unsupported, distinct from not configured"""
+        with self.assertRaises(UnsupportedSearchEngineError) as ctx:
+            SearchEngineFactory(SearchEngineParam(type='solr')).get_search_engine()
+        self.assertNotIsInstance(ctx.exception, SearchEngineNotConfiguredError)
+        self.assertEqual(ctx.exception.engine_type, 'solr')
+
+
+
+class TestNormalizeScores(unittest.TestCase):
+    """normalize_scores test class"""
+
+    def _items(self):
+        return [SearchOutputItem(id='a', score=1.8), SearchOutputItem(id='b', score=1.2), SearchOutputItem(id='c', score=0.6)]
+
+    def test_01_per_query_max(self):
+        """Test per_query_max makes the top score exactly 1.0 and keeps ordering"""
+        items = normalize_scores(self._items(), ScoreNormalization.PER_QUERY_MAX)
+        self.assertEqual(items[0].score, 1.0)
+        self.assertAlmostEqual(items[1].score, 1.2 / 1.8)
+        self.assertEqual([item.id for item in items], ['a', 'b', 'c'])
+
+    def test_02_softmax(self):
+        """Test softmax scores sum to one and keep ordering"""
+        items = normalize_scores(self._items(), ScoreNormalization.SOFTMAX)
+        self.assertAlmostEqual(sum(item.score for item in items), 1.0)
+        self.assertGreater(items[0].score, items[1].score)
+        self.assertGreater(items[1].score, items[2].score)
+
+    def test_03_none_and_invalid(self):
+        """Test none leaves scores untouched and unknown modes are rejected"""
+        items = normalize_scores(self._items(), ScoreNormalization.NONE)
+        self.assertEqual(items[0].score, 1.8)
+        with self.assertRaises(ValueError):
+            SearchInput(score_normalization='zscore')
+
+
+class TestSearchInput(unittest.TestCase):
+    """SearchInput validation test class"""
+
+    def test_01_unknown_projection_field(self):
+        """Test unknown projection fields are rejected"""
+        with self.assertRaises(ValueError):
+            SearchInput(fields=['id', 'payload'])
+        self.assertEqual(SearchInput(fields=['id', 'text']).fields, ['id', 'text'])
+
+    def test_02_unknown_metric(self):
+        """Test unknown metrics are rejected"""
+        with self.assertRaises(ValueError):
+            SearchInput(metric='hamming')
+
+
+class TestInsertData(unittest.TestCase):
+    """InsertData validation test class"""
+
+    def test_01_invalid_boost(self):
+        """Test negative and non-finite boosts are rejected"""
+        for boost in [-1.0, float('nan'), float('inf')]:
+            with self.subTest(boost=boost):
+                with self.assertRaises(ValueError):
+                    InsertData(text='doc', boost=boost)
+        self.assertEqual(InsertData(text='doc', boost=0).boost, 0)
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,157 @@
+#!/usr/bin/env python3
+"""
+Search handler test file
+End-to-end test of the image search endpoint with fake extractor and search engine, and error mapping
+"""
+import unittest
+import os
+import sys
+from unittest.mock import AsyncMock, MagicMock
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from utils.config import init_config
+
+init_config(os.path.join(os.path.dirname(os.path.dirname(os.path.abspath(__file__))), 'config.template.yaml'))
+
+from fastapi import FastAPI
+from fastapi.testclient import TestClient
+
+from handlers.auth import get_current_token
+from handlers.search_handler import router, get_search_service, handle_service_exception
+from handlers.search_service import SearchService
+from processor.core.data import MMData, ImageItem
+from processor.utils.async_dashscope import DashScopeAPIError
+from search_engine.base import SearchOutput, SearchOutputItem, EmbeddingInfo
+
+
+class TestImageSearchEndpoint(unittest.TestCase):
+    """Image search endpoint test class"""
+
+    def setUp(self):
+        self.service = SearchService()
+        self.service.mm_extractor = MagicMock()
+        self.service.mm_extractor.forward = AsyncMock(
+            return_value=MMData(image=ImageItem(image='https://example.com/cat.jpg', image_embedding=[0.1, 0.2])))
+        self.service.search_engine = MagicMock()
+        self.service.search_engine.search = AsyncMock(return_value=SearchOutput(items=[
+            SearchOutputItem(id='doc-1', image='https://example.com/cat2.jpg', score=0.9),
+            SearchOutputItem(id='doc-2', image='https://example.com/dog.jpg', score=0.4),
+        ]))
+        self.service.initialized = True
+
+        app = FastAPI()
+        app.include_router(router, prefix="/api/v1")
+        app.dependency_overrides[get_search_service] = lambda: self.service
+        app.dependency_overrides[get_current_token] = lambda: None
+        self.client = TestClient(app)
+
+    def test_01_empty_url(self):
+        """Test an empty image_url is rejected with 422"""
+        response = self.client.post('/api/v1/search/image', json={'image_url': '  '})
+
+        self.assertEqual(response.status_code, 422)
+        self.service.search_engine.search.assert_not_awaited()
+
+    def test_02_invalid_url(self):
+        """Test a non http(s) image_url is rejected with 422"""
+        for url in ['not a url', 'ftp://example.com/cat.jpg', 'https://']:
+            response = self.client.post('/api/v1/search/image', json={'image_url': url})
+            self.assertEqual(response.status_code, 422, url)
+        self.service.mm_extractor.forward.assert_not_awaited()
+
+    def test_03_success(self):
+        """Test a valid image_url is embedded, searched and returned in rank order"""
+        response = self.client.post('/api/v1/search/image',
+                                    json={'image_url': 'https://example.com/cat.jpg', 'top_k': 2})
+
+        self.assertEqual(response.status_code, 200)
+        body = response.json()
+        self.assertTrue(body['success'])
+        self.assertEqual([item['id'] for item in body['results']], ['doc-1', 'doc-2'])
+
+        search_input = self.service.search_engine.search.await_args.args[0]
+        self.assertEqual(search_input.topk, 2)
+        self.assertEqual(search_input.embeddings[0].label, 'image_embedding')
+        self.assertEqual(search_input.embeddings[0].embedding, [0.1, 0.2])
+
+
+    def test_04_search_options_passthrough(self):
+        """Test search options in the request body reach the engine SearchInput"""
+        response = self.client.post('/api/v1/search/text', json={
+            'query': 'cat', 'exact': False, 'metric': 'cosine', 'score_normalization': 'softmax',
+            'fields': ['text', 'image'], 'include_vectors': True,
+        })
+
+        self.assertEqual(response.status_code, 200)
+        search_input = self.service.search_engine.search.await_args.args[0]
+        self.assertFalse(search_input.exact)
+        self.assertEqual(search_input.metric, 'cosine')
+        self.assertEqual(search_input.score_normalization, 'softmax')
+        self.assertEqual(search_input.fields, ['text', 'image'])
+        self.assertTrue(search_input.include_vectors)
+
+    def test_05_search_options_defaults(self):
+        """Test omitted options keep the engine defaults"""
+        self.client.post('/api/v1/search/multimodal', json={'text': 'cat'})
+
+        search_input = self.service.search_engine.search.await_args.args[0]
+        self.assertTrue(search_input.exact)
+        self.assertIsNone(search_input.metric)
+        self.assertEqual(search_input.fields, [])
+        self.assertFalse(search_input.include_vectors)
+
+    def test_06_invalid_search_options(self):
+        """Test unknown metric or field is rejected before any embedding call"""
+        for body in [{'query': 'cat', 'metric': 'hamming'}, {'query': 'cat', 'fields': ['secret']}]:
+            response = self.client.post('/api/v1/search/text', json=body)
+            self.assertEqual(response.status_code, 422, body)
+        self.service.mm_extractor.forward.assert_not_awaited()
+
+    def test_07_include_vectors(self):
+        """Test result embeddings are returned only when include_vectors is requested"""
+        self.service.search_engine.search.return_value = SearchOutput(items=[
+            SearchOutputItem(id='doc-1', text='cat', score=0.9,
+                             embeddings=[EmbeddingInfo(label='text_embedding', embedding=[0.1, 0.2])]),
+        ])
+        body = self.client.post('/api/v1/search/text', json={'query': 'cat', 'include_vectors': True}).json()
+        self.assertEqual(body['results'][0]['embeddings'], {'text_embedding': [0.1, 0.2]})
+
+        self.service.search_engine.search.return_value = SearchOutput(items=[SearchOutputItem(id='doc-1', text='cat')])
+        body = self.client.post('/api/v1/search/text', json={'query': 'cat'}).json()
+        self.assertIsNone(body['results'][0]['embeddings'])
+
+    def test_08_engine_rejected_option(self):
+        """Test an option the engine cannot honour is a validation error, not a 500"""
+        self.service.search_engine.search.side_effect = ValueError('metric override requires exact search')
+
+        response = self.client.post('/api/v1/search/text', json={'query': 'cat', 'exact': False, 'metric': 'l2'})
+
+        self.assertEqual(response.status_code, 422)
+
+    def test_09_index_stats_engine_unreachable(self):
+        """Test an unreachable search engine on index stats is reported as retryable 503, not a generic 500"""
+        self.service.search_engine.stats = AsyncMock(side_effect=ConnectionError('Connection refused'))
+
+        response = self.client.get('/api/v1/index/stats')
+
+        self.assertEqual(response.status_code, 503)
+
+
+class TestHandleServiceException(unittest.TestCase):
+    """Upstream error to HTTP status mapping test class"""
+
+    def test_01_upstream_auth_error(self):
+        """Test an upstream 401 is reported as 502, not as a bad client request"""
+        err = DashScopeAPIError('Text embedding', 401, code='InvalidApiKey', message='Invalid API-key provided.')
+        self.assertEqual(handle_service_exception(err).status_code, 502)
+
+    def test_02_upstream_invalid_parameter(self):
+        """Test an upstream 400 InvalidParameter is reported as 400"""
+        err = DashScopeAPIError('Text embedding', 400, code='InvalidParameter')
+        self.assertEqual(handle_service_exception(err).status_code, 400)
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -187,5 +187,11 @@ EMBEDDING_LABEL_TEST_CASES = [
     ("vembed", "video_embedding"),
     ("video", "video_embedding"),
     ("vid", "video_embedding"),
-    ("unknown_label", "text_embedding")  # Default mapped to text_embedding
-] 
\ No newline at end of file
+    ("image_text_embedding", "image_text_embedding"),
+    ("img_text", "image_text_embedding"),
+    ("video_text_embedding", "video_text_embedding"),
+    ("vid_text", "video_text_embedding")
+]
+
+# Labels that do not resolve to a mapped vector field, they raise ValueError instead of falling back to text_embedding
+UNMAPPED_EMBEDDING_LABELS = ["unknown_label", "audio_embedding", ""]
@@ -0,0 +1,131 @@
+#!/usr/bin/env python3
+"""
+Bulk index a local directory
+Upload the images and videos under a directory to OSS and insert them with their
+sidecar .txt captions, .txt files without media are inserted as text.
+
+Usage: python tools/bulk_index.py <directory> [--config config.yaml] [--batch-size 20]
+"""
+
+import argparse
+import asyncio
+import os
+import sys
+from dataclasses import dataclass, field
+from typing import Callable, List, Tuple
+
+# Add project root directory to path
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from handlers.models import InsertDataRequest
+from handlers.search_service import SearchService
+from utils.config import init_config
+from utils.directory_loader import DirectoryItem, load_directory
+from utils.logger import get_logger, configure_logging_from_config
+
+logger = get_logger(__name__)
+
+# (local path, file type) -> public URL
+Uploader = Callable[[str, str], str]
+
+
+@dataclass
+class BulkIndexReport:
+    """Outcome of a bulk index run"""
+    total: int = 0
+    inserted: int = 0
+    # (source file or caption, error)
+    failures: List[Tuple[str, str]] = field(default_factory=list)
+
+
+def oss_upload(file_path: str, file_type: str) -> str:
+    """Upload a local file through the configured OSS uploader and return its URL"""
+    from utils.oss_uploader import get_oss_uploader
+    result = get_oss_uploader().upload_file(file_path, file_type)
+    if not result['success']:
+        raise RuntimeError(result.get('error', 'Unknown error'))
+    return result['file_url']
+
+
+def _source(item: DirectoryItem) -> str:
+    return item.image_path or item.video_path or item.text[:50]
+
+
+def to_insert_request(item: DirectoryItem, upload: Uploader) -> InsertDataRequest:
+    """Upload the item's media and build the insert request"""
+    return InsertDataRequest(
+        text=item.text or None,
+        image_url=upload(item.image_path, 'image') if item.image_path else None,
+        video_url=upload(item.video_path, 'video') if item.video_path else None,
+    )
+
+
+async def bulk_index(path: str, service: SearchService, upload: Uploader = oss_upload,
+                     batch_size: int = 20) -> BulkIndexReport:
+    """
+    Index every supported file under path
+
+    Items are inserted in batches, a failed batch is retried item by item so one bad
+    file only fails itself. Failures are collected in the report instead of raised.
+    """
+    items = load_directory(path)
+    report = BulkIndexReport(total=len(items))
+
+    for i in range(0, len(items), batch_size):
+        batch = []
+        for item in items[i:i + batch_size]:
+            try:
+                batch.append((item, to_insert_request(item, upload)))
+            except Exception as e:
+                logger.warning(f"Upload failed for {_source(item)}: {e}")
+                report.failures.append((_source(item), f"upload failed: {e}"))
+        if not batch:
+            continue
+
+        try:
+            report.inserted += await service.batch_insert_data([request for _, request in batch])
+        except Exception as e:
+            logger.warning(f"Batch insert failed, retrying items one by one: {e}")
+            for item, request in batch:
+                try:
+                    await service.insert_data(text=request.text or '', image_url=request.image_url or '',
+                                              video_url=request.video_url or '')
+                    report.inserted += 1
+                except Exception as e:
+                    report.failures.append((_source(item), str(e)))
+
+        logger.info(f"Indexed {report.inserted}/{report.total} items")
+
+    return report
+
+
+async def run(path: str, batch_size: int) -> BulkIndexReport:
+    service = SearchService()
+    await service.initialize()
+    try:
+        return await bulk_index(path, service, batch_size=batch_size)
+    finally:
+        await service.close()
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Bulk index a local directory of images, videos and captions")
+    parser.add_argument('directory', help="Directory to index")
+    parser.add_argument('--config', default='config.yaml', help="Service config file")
+    parser.add_argument('--batch-size', type=int, default=20, help="Items per batch insert")
+    args = parser.parse_args()
+
+    config_manager = init_config(args.config)
+    configure_logging_from_config(config_manager.get_logging_config())
+
+    report = asyncio.run(run(args.directory, args.batch_size))
+
+    print(f"Indexed {report.inserted}/{report.total} items")
+    for source, error in report.failures:
+        print(f"  failed  {source}: {error}")
+    if report.failures:
+        sys.exit(1)
+
+
+if __name__ == '__main__':
+    main()
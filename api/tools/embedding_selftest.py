@@ -0,0 +1,109 @@
+#!/usr/bin/env python3
+"""
+Embedding quality self-test script
+Embed known-similar and known-dissimilar sentence pairs with the configured
+text embedding plugin and check that similar pairs score higher.
+Exit code is non-zero when the ordering is violated.
+
+Usage: python tools/embedding_selftest.py [config.yaml]
+The config is the service config.yaml (its mmextractor section is used) or a standalone pipeline config.
+"""
+
+import asyncio
+import math
+import os
+import sys
+import yaml
+from pathlib import Path
+from typing import List
+
+# Add project root directory to path
+sys.path.append(os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from processor.pipelines.mm_extractor import MMExtractor
+from processor.core import PipelineParam, DataIO
+
+
+# (anchor, similar, dissimilar)
+SELFTEST_CASES = [
+    (
+        "A dog is playing with a ball in the park",
+        "A puppy chases a ball on the grass",
+        "The stock market closed lower today",
+    ),
+    (
+        "How do I reset my account password?",
+        "I forgot my password and need to change it",
+        "The recipe needs two cups of flour",
+    ),
+    (
+        "人工智能正在改变医疗行业",
+        "AI 技术被广泛应用于医学诊断",
+        "今天的天气非常晴朗",
+    ),
+]
+
+
+def cosine_similarity(a: List[float], b: List[float]) -> float:
+    """Cosine similarity of two vectors"""
+    dot = sum(x * y for x, y in zip(a, b))
+    norm_a = math.sqrt(sum(x * x for x in a))
+    norm_b = math.sqrt(sum(y * y for y in b))
+    if norm_a == 0 or norm_b == 0:
+        return 0.0
+    return dot / (norm_a * norm_b)
+
+
+async def embed(extractor: MMExtractor, text: str) -> List[float]:
+    """Embed a single text with the configured text embedding plugin"""
+    result = await extractor.tembed.forward(DataIO(text=text))
+    if not result.embeddings or not result.embeddings[0]:
+        raise ValueError(f"Empty embedding returned for: {text}")
+    return result.embeddings[0]
+
+
+async def run_selftest(config_path: Path) -> bool:
+    """Run all self-test cases, return True when every ordering holds"""
+    with open(config_path, 'r', encoding='utf-8') as f:
+        config = yaml.safe_load(f)
+    config = config.get('mmextractor', config)
+
+    extractor = MMExtractor(PipelineParam.from_dict(config))
+
+    passed = True
+    for anchor, similar, dissimilar in SELFTEST_CASES:
+        anchor_emb = await embed(extractor, anchor)
+        similar_score = cosine_similarity(anchor_emb, await embed(extractor, similar))
+        dissimilar_score = cosine_similarity(anchor_emb, await embed(extractor, dissimilar))
+
+        ok = similar_score > dissimilar_score
+        passed = passed and ok
+        print(f"{'✅ PASS' if ok else '❌ FAIL'}  {anchor}")
+        print(f"    similar    {similar_score:.4f}  {similar}")
+        print(f"    dissimilar {dissimilar_score:.4f}  {dissimilar}")
+
+    return passed
+
+
+def main():
+    print("🔎 Embedding quality self-test")
+    print("=" * 60)
+
+    config_path = Path(sys.argv[1]) if len(sys.argv) > 1 else Path(__file__).parent.parent / 'config.yaml'
+
+    try:
+        passed = asyncio.run(run_selftest(config_path))
+    except Exception as e:
+        print(f"\n❌ Self-test could not run: {e}")
+        sys.exit(2)
+
+    print("=" * 60)
+    if passed:
+        print("✅ Similar pairs scored higher than dissimilar pairs")
+    else:
+        print("❌ Ordering violated, check the embedding model configuration")
+        sys.exit(1)
+
+
+if __name__ == '__main__':
+    main()
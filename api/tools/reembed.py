@@ -0,0 +1,136 @@
+#!/usr/bin/env python3
+"""
+Re-embed an index
+Scan every stored document, run its text and media through the configured extractor again and
+upsert it under the same id, e.g. after switching embedding models with the same dimensions.
+A dimension change needs a new index, see the README.
+
+Usage: python tools/reembed.py [--config config.yaml] [--concurrency 4] [--batch-size 100]
+"""
+
+import argparse
+import asyncio
+import os
+import sys
+from dataclasses import dataclass, field
+from typing import List, Tuple
+
+# Add project root directory to path
+sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
+
+from handlers.search_service import SearchService, build_insert_data
+from processor.core.data import MMData, TextItem, ImageItem, VideoItem
+from processor.pipelines.mm_extractor import MMExtractor
+from processor.utils.concurrency import ConcurrencyLimiter
+from search_engine.base import BaseSearchEngine, InsertData
+from utils.config import init_config
+from utils.logger import get_logger, configure_logging_from_config
+
+logger = get_logger(__name__)
+
+# Extractor errors meaning the stored media URL no longer resolves to a usable file
+_DEAD_IMAGE_ERRORS = ('image format is illegal', 'cannot be opened')
+_DEAD_VIDEO_ERRORS = ('Video URL download error', 'inaccessible')
+
+
+@dataclass
+class ReembedReport:
+    """Outcome of a re-embed run"""
+    total: int = 0
+    updated: int = 0
+    # (document id, error), failed documents keep their previous vectors
+    failures: List[Tuple[str, str]] = field(default_factory=list)
+
+
+def _dead_media_url(doc: InsertData, error: str) -> str:
+    if doc.image and any(marker in error for marker in _DEAD_IMAGE_ERRORS):
+        return doc.image
+    if doc.video and any(marker in error for marker in _DEAD_VIDEO_ERRORS):
+        return doc.video
+    return ''
+
+
+async def reembed_document(doc: InsertData, engine: BaseSearchEngine, extractor: MMExtractor) -> None:
+    """Extract the document again and overwrite it, raises when any stored vector could not be rebuilt"""
+    mm_data = MMData(
+        text=TextItem(text=doc.text) if doc.text else None,
+        image=ImageItem(image=doc.image) if doc.image else None,
+        video=VideoItem(video=doc.video) if doc.video else None
+    )
+    result = await extractor.forward(mm_data)
+
+    data = build_insert_data(result, doc.text, doc.image, doc.video)
+    missing = sorted({info.label for info in doc.embeddings} - {info.label for info in data.embeddings})
+    if missing:
+        warnings = f" ({'; '.join(result.warnings)})" if result.warnings else ''
+        raise ValueError(f"Missing embeddings after extraction: {missing}{warnings}")
+
+    data.id = doc.id
+    data.boost = doc.boost
+    await engine.insert(data)
+
+
+async def reembed(engine: BaseSearchEngine, extractor: MMExtractor, concurrency: int = 4,
+                  batch_size: int = 100) -> ReembedReport:
+    """
+    Re-embed every document of the index in place
+
+    Documents are upserted under their stored id, so an interrupted run is resumed by running it
+    again. Dead media URLs and other failures are collected in the report instead of raised.
+    """
+    report = ReembedReport()
+    limiter = ConcurrencyLimiter(concurrency)
+
+    async def process(doc_id: str) -> None:
+        async with limiter:
+            doc = None
+            try:
+                doc = await engine.get(doc_id)
+                await reembed_document(doc, engine, extractor)
+                report.updated += 1
+            except Exception as e:
+                error = str(e)
+                dead_url = _dead_media_url(doc, error) if doc else ''
+                if dead_url:
+                    error = f"dead media URL {dead_url}: {error}"
+                logger.warning(f"Re-embedding {doc_id} failed: {error}")
+                report.failures.append((doc_id, error))
+
+    async for batch in engine.scan(batch_size):
+        report.total += len(batch)
+        await asyncio.gather(*(process(item.id) for item in batch))
+        logger.info(f"Re-embedded {report.updated}/{report.total} documents")
+
+    return report
+
+
+async def run(concurrency: int, batch_size: int) -> ReembedReport:
+    service = SearchService()
+    await service.initialize()
+    try:
+        return await reembed(service.search_engine, service.mm_extractor, concurrency, batch_size)
+    finally:
+        await service.close()
+
+
+def main():
+    parser = argparse.ArgumentParser(description="Re-embed every document of the configured index")
+    parser.add_argument('--config', default='config.yaml', help="Service config file")
+    parser.add_argument('--concurrency', type=int, default=4, help="Documents re-embedded at the same time")
+    parser.add_argument('--batch-size', type=int, default=100, help="Documents per scan batch")
+    args = parser.parse_args()
+
+    config_manager = init_config(args.config)
+    configure_logging_from_config(config_manager.get_logging_config())
+
+    report = asyncio.run(run(args.concurrency, args.batch_size))
+
+    print(f"Re-embedded {report.updated}/{report.total} documents")
+    for doc_id, error in report.failures:
+        print(f"  failed  {doc_id}: {error}")
+    if report.failures:
+        sys.exit(1)
+
+
+if __name__ == '__main__':
+    main()
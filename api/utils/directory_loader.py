@@ -0,0 +1,81 @@
+"""
+Local directory loader
+Walk a directory of images, videos and text files and pair media with sidecar .txt captions
+"""
+
+import os
+from dataclasses import dataclass
+from typing import Dict, List
+
+from .logger import get_logger
+
+logger = get_logger(__name__)
+
+IMAGE_EXTENSIONS = {'.jpg', '.jpeg', '.png', '.gif', '.bmp', '.webp'}
+VIDEO_EXTENSIONS = {'.mp4', '.avi', '.mov', '.wmv', '.flv', '.webm'}
+CAPTION_EXTENSION = '.txt'
+
+
+@dataclass
+class DirectoryItem:
+    """One item to index, media paths are local files"""
+    text: str = ''
+    image_path: str = ''
+    video_path: str = ''
+
+
+def _read_caption(path: str) -> str:
+    with open(path, 'r', encoding='utf-8') as f:
+        return f.read().strip()
+
+
+def load_directory(path: str) -> List[DirectoryItem]:
+    """
+    Build items from every supported file under path
+
+    An image or video with a .txt file of the same name in the same folder gets that file as its caption,
+    a .txt file without matching media becomes a text item. Hidden files are ignored and other file types
+    are skipped with a warning.
+
+    Args:
+        path: Directory to walk recursively
+
+    Returns:
+        Items in a stable order (sorted by path)
+    """
+    if not os.path.isdir(path):
+        raise NotADirectoryError(f"Not a directory: {path}")
+
+    items = []
+    for root, dirs, files in os.walk(path):
+        dirs[:] = sorted(d for d in dirs if not d.startswith('.'))
+
+        # Group files of this folder by name without extension
+        groups: Dict[str, Dict[str, List[str]]] = {}
+        for name in sorted(files):
+            if name.startswith('.'):
+                continue
+            stem, ext = os.path.splitext(name)
+            ext = ext.lower()
+            file_path = os.path.join(root, name)
+            group = groups.setdefault(stem, {'images': [], 'videos': [], 'captions': []})
+            if ext in IMAGE_EXTENSIONS:
+                group['images'].append(file_path)
+            elif ext in VIDEO_EXTENSIONS:
+                group['videos'].append(file_path)
+            elif ext == CAPTION_EXTENSION:
+                group['captions'].append(file_path)
+            else:
+                logger.warning(f"Skipping unsupported file type: {file_path}")
+
+        for stem in sorted(groups):
+            group = groups[stem]
+            caption = _read_caption(group['captions'][0]) if group['captions'] else ''
+            for image_path in group['images']:
+                items.append(DirectoryItem(text=caption, image_path=image_path))
+            for video_path in group['videos']:
+                items.append(DirectoryItem(text=caption, video_path=video_path))
+            if caption and not group['images'] and not group['videos']:
+                items.append(DirectoryItem(text=caption))
+
+    return items
@@ -1,10 +1,73 @@
+import json
 import logging
 import sys
-from typing import Optional
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Optional, Dict, Any, TextIO
 
 # Global logger configuration
 _logger_initialized = False
 
+TEXT_FORMAT = '%(asctime)s - %(name)s - %(levelname)s - %(message)s'
+
+
+class JsonFormatter(logging.Formatter):
+    """Format log records as one JSON object per line"""
+
+    def format(self, record: logging.LogRecord) -> str:
+        entry = {
+            'timestamp': datetime.fromtimestamp(record.created, tz=timezone.utc).isoformat(),
+            'level': record.levelname,
+            'logger': record.name,
+            'message': record.getMessage(),
+        }
+        if record.exc_info:
+            entry['exception'] = self.formatException(record.exc_info)
+        return json.dumps(entry, ensure_ascii=False)
+
+
+def configure_logging(level: str = 'INFO', log_format: str = 'text', stream: Optional[TextIO] = None,
+                      file_path: Optional[str] = None) -> None:
+    """
+    Configure root logger output
+
+    Args:
+        level: Log level name
+        log_format: "text" (default) or "json"
+        stream: Writable stream for log output, defaults to stdout when no file_path is given
+        file_path: Also write logs to this file when set
+    """
+    global _logger_initialized
+
+    if log_format not in ('text', 'json'):
+        raise ValueError(f'Unsupported log format: {log_format}')
+    formatter = JsonFormatter() if log_format == 'json' else logging.Formatter(TEXT_FORMAT)
+
+    handlers = []
+    if stream is not None or file_path is None:
+        handlers.append(logging.StreamHandler(stream or sys.stdout))
+    if file_path:
+        Path(file_path).parent.mkdir(parents=True, exist_ok=True)
+        handlers.append(logging.FileHandler(file_path, encoding='utf-8'))
+    for handler in handlers:
+        handler.setFormatter(formatter)
+
+    logging.basicConfig(level=level.upper(), handlers=handlers, force=True)
+    _logger_initialized = True
+
+
+def configure_logging_from_config(config: Dict[str, Any]) -> None:
+    """Configure logging from the logging section of config.yaml"""
+    output = config.get('output', 'stdout')
+    file_path = config.get('file', {}).get('path') if 'file' in output else None
+    configure_logging(
+        level=config.get('level', 'INFO'),
+        log_format=config.get('format', 'text'),
+        stream=sys.stdout if 'stdout' in output else None,
+        file_path=file_path,
+    )
+
+
 def get_logger(name: Optional[str] = None) -> logging.Logger:
     """
     Get logger
@@ -21,7 +84,7 @@ def get_logger(name: Optional[str] = None) -> logging.Logger:
         # Configure root logger
         logging.basicConfig(
             level=logging.INFO,
-            format='%(asctime)s - %(name)s - %(levelname)s - %(message)s',
+            format=TEXT_FORMAT,
             handlers=[
                 logging.StreamHandler(sys.stdout),
             ]
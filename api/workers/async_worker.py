@@ -3,9 +3,11 @@ Async worker for processing background tasks
 """
 
 import asyncio
+import signal
 import time
-from typing import Dict, Any
+from typing import Dict, Any, Optional
 from utils.async_task_manager import get_task_manager
+from utils.config import get_config_manager
 from handlers.search_service import SearchService
 from utils.logger import get_logger
 
@@ -19,6 +21,9 @@ class AsyncWorker:
         self.task_manager = get_task_manager()
         self.search_service = None
         self.running = False
+        # Running task -> task id, so shutdown can wait for or requeue them
+        self.in_flight: Dict[asyncio.Task, str] = {}
+        self._stop_event: Optional[asyncio.Event] = None
     
     async def initialize(self):
         """Initialize the worker"""
@@ -91,7 +96,7 @@ class AsyncWorker:
             )
             
             # Perform data insertion
-            await self.search_service.insert_data(
+            warnings = await self.search_service.insert_data(
                 text=text,
                 image_url=image_url,
                 video_url=video_url
@@ -109,6 +114,7 @@ class AsyncWorker:
             result = {
                 'inserted_count': 1,
                 'processing_time': time.time(),
+                'warnings': warnings or [],
                 'data': {
                     'text': text,
                     'image_url': image_url,
@@ -147,13 +153,15 @@ class AsyncWorker:
             
             # Process each item
             inserted_count = 0
+            warnings = []
             for i, data_item in enumerate(data_list):
                 try:
-                    await self.search_service.insert_data(
+                    item_warnings = await self.search_service.insert_data(
                         text=data_item.get('text'),
                         image_url=data_item.get('image_url'),
                         video_url=data_item.get('video_url')
                     )
+                    warnings.extend(f'item {i}: {w}' for w in item_warnings or [])
                     inserted_count += 1
                     
                     # Update progress
@@ -174,7 +182,8 @@ class AsyncWorker:
                 'inserted_count': inserted_count,
                 'total_items': total_items,
                 'processing_time': time.time(),
-                'success_rate': inserted_count / total_items if total_items > 0 else 0
+                'success_rate': inserted_count / total_items if total_items > 0 else 0,
+                'warnings': warnings
             }
             
             self.task_manager.update_task_status(
@@ -190,8 +199,9 @@ class AsyncWorker:
             raise
     
     async def run(self, check_interval: int = 5):
-        """Run the worker loop"""
+        """Run the worker loop until stop() is called, in-flight tasks are left to shutdown()"""
         self.running = True
+        self._stop_event = asyncio.Event()
         logger.info("Async worker started")
         
         try:
@@ -207,18 +217,20 @@ class AsyncWorker:
                         tasks = []
                         for task_info in pending_tasks:
                             task = asyncio.create_task(self.process_task(task_info))
+                            self.in_flight[task] = task_info['task_id']
+                            task.add_done_callback(lambda t: self.in_flight.pop(t, None))
                             tasks.append(task)
                         
-                        # Wait for all tasks to complete
+                        # Wait for all tasks to complete, or return early on stop so shutdown can apply its deadline
                         if tasks:
-                            await asyncio.gather(*tasks, return_exceptions=True)
+                            await self._wait_or_stop(asyncio.gather(*tasks, return_exceptions=True))
                     
                     # Wait before next check
-                    await asyncio.sleep(check_interval)
+                    await self._sleep_or_stop(check_interval)
                     
                 except Exception as e:
                     logger.error(f"Error in worker loop: {e}")
-                    await asyncio.sleep(check_interval)
+                    await self._sleep_or_stop(check_interval)
                     
         except KeyboardInterrupt:
             logger.info("Async worker stopped by user")
@@ -227,10 +239,64 @@ class AsyncWorker:
         finally:
             self.running = False
     
+    async def _wait_or_stop(self, batch: asyncio.Future):
+        """Wait for a batch of tasks, returning early when stop() is called, the batch keeps running"""
+        stop_waiter = asyncio.create_task(self._stop_event.wait())
+        await asyncio.wait({batch, stop_waiter}, return_when=asyncio.FIRST_COMPLETED)
+        stop_waiter.cancel()
+    
+    async def _sleep_or_stop(self, seconds: float):
+        """Sleep between polls, returning early when stop() is called"""
+        try:
+            await asyncio.wait_for(self._stop_event.wait(), timeout=seconds)
+        except asyncio.TimeoutError:
+            pass
+    
     def stop(self):
-        """Stop the worker"""
+        """Stop the worker from picking up new tasks"""
         self.running = False
+        if self._stop_event is not None:
+            self._stop_event.set()
         logger.info("Async worker stop requested")
+    
+    async def shutdown(self, timeout: float = 30.0) -> int:
+        """
+        Stop picking up tasks and wait up to timeout seconds for in-flight ones
+        
+        Tasks still running at the deadline are cancelled and put back to pending so the
+        next worker retries them, re-inserting the same content overwrites by document id.
+        
+        Returns:
+            Number of tasks that could not be finished in time
+        """
+        self.stop()
+        in_flight = dict(self.in_flight)
+        if not in_flight:
+            return 0
+        
+        logger.info(f"Draining {len(in_flight)} in-flight tasks, timeout {timeout}s")
+        _, not_done = await asyncio.wait(in_flight.keys(), timeout=timeout)
+        for task in not_done:
+            task.cancel()
+        await asyncio.gather(*not_done, return_exceptions=True)
+        
+        for task in not_done:
+            self.task_manager.update_task_status(
+                in_flight[task],
+                status='pending',
+                progress=0.0,
+                message='Requeued after worker shutdown'
+            )
+        if not_done:
+            logger.warning(f"{len(not_done)} tasks not finished before shutdown deadline, requeued")
+        return len(not_done)
+    
+    async def close(self):
+        """Close the worker and release search service"""
+        if self.search_service:
+            await self.search_service.close()
+            self.search_service = None
+        logger.info("Async worker closed")
 
 
 # Global worker instance
@@ -238,9 +304,16 @@ worker = AsyncWorker()
 
 
 async def start_worker():
-    """Start the async worker"""
+    """Start the async worker, SIGTERM/SIGINT drain in-flight tasks before closing"""
     await worker.initialize()
-    await worker.run()
+    loop = asyncio.get_running_loop()
+    for sig in (signal.SIGTERM, signal.SIGINT):
+        loop.add_signal_handler(sig, worker.stop)
+    try:
+        await worker.run()
+    finally:
+        await worker.shutdown(get_config_manager().get_config('performance.worker_shutdown_timeout', 30))
+        await worker.close()
 
 
 def get_worker() -> AsyncWorker:
@@ -11,7 +11,8 @@ import os
 sys.path.insert(0, os.path.dirname(os.path.dirname(os.path.abspath(__file__))))
 
 from workers.async_worker import start_worker
-from utils.logger import get_logger
+from utils.logger import get_logger, configure_logging_from_config
+from utils.config import init_config
 
 logger = get_logger(__name__)
 
@@ -19,6 +20,8 @@ logger = get_logger(__name__)
 async def main():
     """Main function to start the worker"""
     try:
+        config_manager = init_config()
+        configure_logging_from_config(config_manager.get_logging_config())
         logger.info("Starting MoleSearch async worker...")
         await start_worker()
     except KeyboardInterrupt:
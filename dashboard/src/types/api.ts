@@ -14,6 +14,8 @@ export interface SearchResultItem {
   image_text: string;
   video_text: string;
   score: number;
+  highlights?: string[];
+  embeddings?: Record<string, number[]> | null;
 }
 
 // All data item
@@ -57,6 +59,7 @@ export interface InsertResponse {
   message: string;
   inserted_count?: number;
   processing_time?: number;
+  warnings?: string[];
 }
 
 // Batch insert request
@@ -148,26 +151,35 @@ export interface DeleteApiKeyResponse {
   message: string;
 }
 
+// Search options shared by query search requests
+export interface SearchOptions {
+  exact?: boolean;
+  metric?: 'cosine' | 'dot_product' | 'l2';
+  score_normalization?: 'none' | 'per_query_max' | 'softmax';
+  fields?: string[];
+  include_vectors?: boolean;
+}
+
 // Text search request
-export interface TextSearchRequest {
+export interface TextSearchRequest extends SearchOptions {
   query: string;
   top_k: number;
 }
 
 // Image search request
-export interface ImageSearchRequest {
+export interface ImageSearchRequest extends SearchOptions {
   image_url: string;
   top_k: number;
 }
 
 // Video search request
-export interface VideoSearchRequest {
+export interface VideoSearchRequest extends SearchOptions {
   video_url: string;
   top_k: number;
 }
 
 // Multimodal search request
-export interface MultimodalSearchRequest {
+export interface MultimodalSearchRequest extends SearchOptions {
   text?: string;
   image_url?: string;
   video_url?: string;